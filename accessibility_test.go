@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestGamePageAccessible(t *testing.T) {
+	for _, id := range []string{TEST_ID_1, TEST_ID_2} {
+		dataStore = GameStore{datastore: testDataStore()}
+		addTestGames(dataStore)
+
+		wt := webTest(t)
+		wt.setParam("id", id)
+		defer wt.showBodyOnFail()
+
+		gamePage(wt.ec)
+
+		wt.confirmSuccessResponse()
+		wt.confirmAccessible()
+	}
+}
+
+func TestGamePageWithClockAccessible(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	addTestGames(dataStore)
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	game.Clock.Enabled = true
+	dataStore.putGame(context.TODO(), TEST_ID_1, game)
+
+	wt := webTest(t)
+	wt.setParam("id", TEST_ID_1)
+	defer wt.showBodyOnFail()
+
+	gamePage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmAccessible()
+}
+
+func TestGameRecordTable(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	addTestGames(dataStore)
+
+	wt := webTest(t)
+	wt.setParam("id", TEST_ID_1)
+	defer wt.showBodyOnFail()
+
+	gamePage(wt.ec)
+
+	rows := wt.document().Find("#game_record tbody tr.eventrow")
+	if rows.Length() != 4 {
+		t.Errorf("Expected 4 events in the game record, got %d", rows.Length())
+	}
+	if id, _ := rows.First().Attr("data-event-id"); id == "" {
+		t.Error("Event rows should have the event ID")
+	}
+	if label, _ := wt.document().Find("#btn_home_goal").Attr("aria-label"); !strings.Contains(label, "Reds") {
+		t.Errorf("Event button should name the team, got %q", label)
+	}
+}
+
+func TestNewEventPageAccessible(t *testing.T) {
+	for _, eventType := range []string{"HG", "AP", "HS", "AT", "HK"} {
+		dataStore = GameStore{datastore: testDataStore()}
+		setupDataStore(dataStore)
+
+		wt := webTest(t)
+		wt.setQuery("game", TEST_ID_1)
+		wt.setQuery("type", eventType)
+		defer wt.showBodyOnFail()
+
+		newEventPage(wt.ec)
+
+		wt.confirmSuccessResponse()
+		wt.confirmAccessible()
+	}
+}
+
+func TestAccessibilityProblems(t *testing.T) {
+	page := `<html><body>
+		<img src="/logo.png">
+		<input type="text" name="unlabelled">
+		<a href="/empty"></a>
+		<table id="plain"><tr><th>Heading</th></tr></table>
+		<div id="twice"></div><div id="twice" tabindex="3"></div>
+	</body></html>`
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+
+	problems := strings.Join(accessibilityProblems(doc), "\n")
+
+	for _, expected := range []string{"no language", "main landmark", "image /logo.png", "form field unlabelled", "link or button /empty",
+		"table plain has no caption", `header "Heading"`, "id twice", "tabindex 3"} {
+		if !strings.Contains(problems, expected) {
+			t.Errorf("Expected problem %q in:\n%s", expected, problems)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Static files are served from URLs that include a hash of their content, so that browsers
+// can cache them for a long time and still pick up changes straight away.
+const HASHED_ASSET_PREFIX = "/static/v/"
+
+const LONG_CACHE = "public, max-age=31536000, immutable"
+const SHORT_CACHE = "public, max-age=3600"
+
+const HASH_LENGTH = 12
+
+// Maps the path of each static file to its content-hashed URL.
+type AssetManifest map[string]string
+
+func assetHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])[:HASH_LENGTH]
+}
+
+// Hashes every static file.
+func buildAssetManifest(files fs.FS) (AssetManifest, error) {
+	manifest := make(AssetManifest)
+	err := fs.WalkDir(files, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		manifest[name] = HASHED_ASSET_PREFIX + assetHash(content) + "/" + name
+		return nil
+	})
+	return manifest, err
+}
+
+// Returns the URL for a static file, e.g. "clock.js". Files that are not in the manifest
+// are served from their plain URL.
+func (manifest AssetManifest) URL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if url, ok := manifest[name]; ok {
+		return url
+	}
+	return "/static/" + name
+}
+
+func addAssetHandlers(e *echo.Echo, files fs.FS) {
+	e.GET(HASHED_ASSET_PREFIX+":hash/*", func(c echo.Context) error {
+		return hashedAsset(c, files)
+	})
+	e.GET("/static/*", func(c echo.Context) error {
+		return serveAsset(c, files, c.Param("*"), SHORT_CACHE)
+	})
+	e.GET("/robots.txt", func(c echo.Context) error {
+		return serveAsset(c, files, "robots.txt", SHORT_CACHE)
+	})
+}
+
+// Serves a static file from its hashed URL. Only a URL with the current hash of the file
+// can be cached for long, since an old hash now gets different content.
+func hashedAsset(c echo.Context, files fs.FS) error {
+	name := c.Param("*")
+	content, err := fs.ReadFile(files, path.Clean(name))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+	cache := LONG_CACHE
+	if c.Param("hash") != assetHash(content) {
+		cache = "no-cache"
+	}
+	return serveAsset(c, files, name, cache)
+}
+
+func serveAsset(c echo.Context, files fs.FS, name string, cache string) error {
+	content, err := fs.ReadFile(files, path.Clean(name))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if path.Ext(name) == ".webmanifest" {
+		contentType = "application/manifest+json"
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	c.Response().Header().Set("Cache-Control", cache)
+	return c.Blob(http.StatusOK, contentType, content)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"io/fs"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func assetServer() *echo.Echo {
+	e := echo.New()
+	addAssetHandlers(e, staticFiles(false))
+	return e
+}
+
+func TestAssetURLIncludesHash(t *testing.T) {
+	url := testTemplates().assets.URL("clock.js")
+	if !strings.HasPrefix(url, HASHED_ASSET_PREFIX) || !strings.HasSuffix(url, "/clock.js") {
+		t.Errorf("Unexpected asset URL %s", url)
+	}
+	if testTemplates().assets.URL("/clock.js") != url {
+		t.Error("Leading slash should be ignored")
+	}
+	if testTemplates().assets.URL("nosuchfile.js") != "/static/nosuchfile.js" {
+		t.Error("Unknown asset should use the plain URL")
+	}
+}
+
+func TestHashedAssetCachedForLong(t *testing.T) {
+	resp := getPage(assetServer(), testTemplates().assets.URL("layout.css"))
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Got status %d", resp.Code)
+	}
+	if resp.Header().Get("Cache-Control") != LONG_CACHE {
+		t.Errorf("Unexpected cache header %s", resp.Header().Get("Cache-Control"))
+	}
+	if !strings.HasPrefix(resp.Header().Get("Content-Type"), "text/css") {
+		t.Errorf("Unexpected content type %s", resp.Header().Get("Content-Type"))
+	}
+}
+
+func TestOutdatedHashNotCached(t *testing.T) {
+	resp := getPage(assetServer(), HASHED_ASSET_PREFIX+"000000000000/layout.css")
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Got status %d", resp.Code)
+	}
+	if resp.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("Unexpected cache header %s", resp.Header().Get("Cache-Control"))
+	}
+}
+
+func TestPlainAssetURL(t *testing.T) {
+	resp := getPage(assetServer(), "/static/clock.js")
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Got status %d", resp.Code)
+	}
+	if resp.Header().Get("Cache-Control") != SHORT_CACHE {
+		t.Errorf("Unexpected cache header %s", resp.Header().Get("Cache-Control"))
+	}
+}
+
+func TestMissingAsset(t *testing.T) {
+	e := assetServer()
+	for _, url := range []string{"/static/nosuchfile.css", HASHED_ASSET_PREFIX + "abc/nosuchfile.css", "/static/../main.go"} {
+		if resp := getPage(e, url); resp.Code != http.StatusNotFound {
+			t.Errorf("Got status %d for %s", resp.Code, url)
+		}
+	}
+}
+
+func TestPagesUseNoExternalResources(t *testing.T) {
+	wt := webTest(t)
+	defer wt.showBodyOnFail()
+
+	homePage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	body := wt.resp.Body.String()
+	if strings.Contains(body, "cdn.jsdelivr.net") {
+		t.Error("Page should not load anything from a CDN")
+	}
+	if !strings.Contains(body, testTemplates().assets.URL("layout.css")) {
+		t.Error("Page should use the hashed stylesheet URL")
+	}
+}
+
+// layout.css only has the Bootstrap classes the templates use, so a template using any other
+// Bootstrap class would quietly lose its styling.
+func TestLayoutCoversBootstrapClasses(t *testing.T) {
+	bootstrapClass := regexp.MustCompile(`^((container|row|col|btn|nav|navbar|table|alert|badge|card|dropdown|modal|collapse|visually-hidden)(-|$)|(d|m[trblxyse]?|p[trblxyse]?|g[xy]?|text|bg|form|input-group|justify-content|align-items|flex|w|h|float|list|fw|fs)-)`)
+	classAttribute := regexp.MustCompile(`class="([^"]*)"`)
+
+	layout, _ := fs.ReadFile(staticFiles(false), "layout.css")
+	pages, _ := fs.Glob(templateFiles(false), "*.html")
+	for _, page := range pages {
+		html, _ := fs.ReadFile(templateFiles(false), page)
+		for _, attribute := range classAttribute.FindAllStringSubmatch(string(html), -1) {
+			for _, class := range strings.Fields(attribute[1]) {
+				if bootstrapClass.MatchString(class) && !regexp.MustCompile(`\.`+regexp.QuoteMeta(class)+`\b`).Match(layout) {
+					t.Errorf("%s uses Bootstrap class %s, which is not in layout.css", page, class)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A GameChange is a single entry in the append-only log of changes made to a game. Signing in
+// doesn't keep a session yet, so there is no signed-in user to record, and changes are traced
+// by the editor's address and the request's trace ID instead.
+type GameChange struct {
+	Action     string
+	Before     string
+	After      string
+	Timestamp  time.Time
+	RemoteAddr string // Kept for investigating misuse, but not shown on the history page
+	TraceID    string
+}
+
+type ChangeLog struct {
+	GameID  string
+	Changes []GameChange
+}
+
+// Creates a change log entry, taking the details of who made the change from the request context.
+func NewGameChange(ctx context.Context, action string, before string, after string) GameChange {
+	change := GameChange{
+		Action:    action,
+		Before:    before,
+		After:     after,
+		Timestamp: time.Now(),
+	}
+
+	if values, ok := ctx.Value(GameRequestKey).(GameRequestContext); ok {
+		change.RemoteAddr = values.RemoteAddr
+		change.TraceID = values.TraceID
+	}
+
+	return change
+}
+
+// Returns a one-line description of an event for the change log.
+func DescribeEvent(event Event) string {
+	parts := []string{fmt.Sprintf("P%d %s", event.Period, event.ClockTime)}
+	if event.HomeAway != "" {
+		parts = append(parts, event.HomeAway)
+	}
+	parts = append(parts, event.EventType)
+	if event.Category != "" {
+		parts = append(parts, "("+event.Category+")")
+	}
+	if event.Player > 0 {
+		parts = append(parts, fmt.Sprintf("#%d", event.Player))
+	}
+	if event.Assist1 > 0 {
+		parts = append(parts, fmt.Sprintf("A:#%d", event.Assist1))
+	}
+	if event.Assist2 > 0 {
+		parts = append(parts, fmt.Sprintf("A:#%d", event.Assist2))
+	}
+	if event.Minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%d min", event.Minutes))
+	}
+	if event.Outcome != "" {
+		parts = append(parts, event.Outcome)
+	}
+	if event.Zone != "" {
+		parts = append(parts, event.Zone)
+	}
+	if event.Opponent > 0 {
+		parts = append(parts, fmt.Sprintf("v #%d", event.Opponent))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Returns a one-line description of a roster entry for the change log.
+func DescribePlayer(homeAway string, player Player) string {
+	text := fmt.Sprintf("%s #%d %s", homeAway, player.Number, player.Name)
+	if player.Position != "" {
+		text += " " + player.Position
+	}
+	if player.Role != "" {
+		text += " (" + player.Role + ")"
+	}
+	if player.StartingGoalie {
+		text += " starting goalie"
+	}
+	if player.Scratched {
+		text += " scratched"
+	}
+	return strings.TrimSpace(text)
+}
+
+// Returns the changes with the most recent first.
+func (log ChangeLog) Latest() []GameChange {
+	changes := make([]GameChange, len(log.Changes))
+	for n, change := range log.Changes {
+		changes[len(log.Changes)-1-n] = change
+	}
+	return changes
+}
+
+// Returns a one-line description of the officials and team staff for the change log.
+func DescribeOfficials(game Game) string {
+	var names []string
+	for _, name := range []string{
+		game.Officials.Referee1, game.Officials.Referee2,
+		game.Officials.Linesman1, game.Officials.Linesman2,
+		game.Officials.Scorekeeper, game.Officials.Timekeeper,
+		game.HomeStaff.Coach, game.HomeStaff.Manager,
+		game.AwayStaff.Coach, game.AwayStaff.Manager,
+	} {
+		if strings.TrimSpace(name) != "" {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	return strings.Join(names, ", ")
+}
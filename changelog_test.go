@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDescribeEvent(t *testing.T) {
+	game := testGame1()
+
+	text := DescribeEvent(game.Events[1])
+	if text != "P1 18:30 Home Goal (Even) #41 A:#89 A:#93" {
+		t.Errorf("Unexpected goal description: %s", text)
+	}
+
+	text = DescribeEvent(game.Events[0])
+	if text != "P2 14:25 Away Penalty (Slash) #50 2 min" {
+		t.Errorf("Unexpected penalty description: %s", text)
+	}
+}
+
+func TestNewGameChange(t *testing.T) {
+	values := GameRequestContext{RemoteAddr: "10.1.2.3", TraceID: "abc123"}
+	ctx := context.WithValue(context.Background(), GameRequestKey, values)
+
+	change := NewGameChange(ctx, "Delete event", "P1 Goal", "")
+
+	if change.RemoteAddr != "10.1.2.3" || change.TraceID != "abc123" {
+		t.Errorf("Request details not recorded: %+v", change)
+	}
+	if change.Timestamp.IsZero() {
+		t.Error("Change has no timestamp")
+	}
+}
+
+func TestRecordChange(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	ctx := context.Background()
+
+	store.recordChange(ctx, "GAME1", GameChange{Action: "First"})
+	store.recordChange(ctx, "GAME1", GameChange{Action: "Second"})
+
+	log := store.getChangeLog(ctx, "GAME1")
+	if len(log.Changes) != 2 {
+		t.Fatalf("Unexpected number of changes: %d", len(log.Changes))
+	}
+	if log.Latest()[0].Action != "Second" {
+		t.Errorf("Latest change not first: %v", log.Latest())
+	}
+}
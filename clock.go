@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const PERIOD_SECONDS = 20 * 60
+
+// A GameClock tracks the period clock on the server, so that every device viewing the
+// game sees the same time. While running, the time remaining is calculated from the
+// time the clock was last started.
+type GameClock struct {
+	Enabled   bool
+	Period    int
+	Running   bool
+	Remaining int
+	StartedAt time.Time
+}
+
+func NewGameClock(period int) GameClock {
+	if period < 1 {
+		period = 1
+	}
+	return GameClock{
+		Enabled:   true,
+		Period:    period,
+		Remaining: PERIOD_SECONDS,
+	}
+}
+
+// Returns the number of seconds left in the period at the specified time.
+func (clock GameClock) RemainingAt(now time.Time) int {
+	remaining := clock.Remaining
+	if clock.Running {
+		remaining -= int(now.Sub(clock.StartedAt).Seconds())
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// Returns the clock time that would be shown on the rink clock at the specified time.
+func (clock GameClock) ClockTimeAt(now time.Time) EventTime {
+	remaining := clock.RemainingAt(now)
+	return eventTime(remaining/60, remaining%60)
+}
+
+func (clock *GameClock) Start(now time.Time) {
+	if clock.Running || clock.RemainingAt(now) == 0 {
+		return
+	}
+	clock.Running = true
+	clock.StartedAt = now
+}
+
+func (clock *GameClock) Stop(now time.Time) {
+	if !clock.Running {
+		return
+	}
+	clock.Remaining = clock.RemainingAt(now)
+	clock.Running = false
+}
+
+// Sets the clock to a specific time, stopping it if it is running.
+func (clock *GameClock) Set(period int, remaining int) {
+	if remaining < 0 {
+		remaining = 0
+	} else if remaining > PERIOD_SECONDS {
+		remaining = PERIOD_SECONDS
+	}
+	if period > LAST_PERIOD {
+		period = LAST_PERIOD
+	}
+	if period > 0 {
+		clock.Period = period
+	}
+	clock.Remaining = remaining
+	clock.Running = false
+}
+
+func (clock *GameClock) NextPeriod() {
+	clock.Set(clock.Period+1, PERIOD_SECONDS)
+}
+
+// The state of the game clock at a particular time, for display.
+type ClockView struct {
+	GameClock
+	ClockTime EventTime
+	Seconds   int
+}
+
+func clockView(clock GameClock, now time.Time) ClockView {
+	return ClockView{
+		GameClock: clock,
+		ClockTime: clock.ClockTimeAt(now),
+		Seconds:   clock.RemainingAt(now),
+	}
+}
+
+// Describes the state of the game clock, e.g. for the change log.
+func (clock ClockView) Describe() string {
+	if !clock.Enabled {
+		return "Clock off"
+	}
+	state := "stopped"
+	if clock.Running {
+		state = "running"
+	}
+	return fmt.Sprintf("P%d %s %s", clock.Period, clock.ClockTime, state)
+}
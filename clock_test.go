@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGameClockRunning(t *testing.T) {
+	start := time.Date(2024, 5, 27, 19, 0, 0, 0, time.UTC)
+	clock := NewGameClock(0)
+
+	if clock.Period != 1 || clock.ClockTimeAt(start) != "20:00" {
+		t.Errorf("Unexpected new clock: %+v", clock)
+	}
+
+	clock.Start(start)
+	if clock.ClockTimeAt(start.Add(90*time.Second)) != "18:30" {
+		t.Errorf("Unexpected running clock time: %s", clock.ClockTimeAt(start.Add(90*time.Second)))
+	}
+
+	clock.Stop(start.Add(100 * time.Second))
+	if clock.Running || clock.Remaining != PERIOD_SECONDS-100 {
+		t.Errorf("Unexpected stopped clock: %+v", clock)
+	}
+	if clock.ClockTimeAt(start.Add(time.Hour)) != "18:20" {
+		t.Error("Stopped clock should not count down")
+	}
+
+	clock.Start(start.Add(time.Hour))
+	if clock.RemainingAt(start.Add(3*time.Hour)) != 0 {
+		t.Error("Clock should not count below zero")
+	}
+}
+
+func TestGameClockSet(t *testing.T) {
+	clock := NewGameClock(1)
+
+	clock.Set(2, 75)
+	if clock.Period != 2 || clock.ClockTimeAt(time.Now()) != "01:15" {
+		t.Errorf("Unexpected clock after set: %+v", clock)
+	}
+
+	clock.NextPeriod()
+	if clock.Period != 3 || clock.Remaining != PERIOD_SECONDS {
+		t.Errorf("Unexpected clock after next period: %+v", clock)
+	}
+
+	clock.NextPeriod()
+	clock.NextPeriod()
+	if clock.Period != LAST_PERIOD {
+		t.Errorf("Clock should stop at the last period: %+v", clock)
+	}
+	clock.Set(9, 0)
+	if clock.Period != LAST_PERIOD {
+		t.Errorf("Clock should not be set beyond the last period: %+v", clock)
+	}
+}
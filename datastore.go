@@ -1,10 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
+	"sync"
+	"time"
 )
 
 type GameStore struct {
@@ -13,15 +18,20 @@ type GameStore struct {
 
 const GAMES_COLLECTION = "Games"
 const LISTS_COLLECTION = "Lists"
+const TEAMS_COLLECTION = "Teams"
+const CHANGES_COLLECTION = "Changes"
+const CHANGE_ENTRIES = "Entries"
 
 var Collections = map[string]string{
 	"game": GAMES_COLLECTION,
 	"list": LISTS_COLLECTION,
+	"team": TEAMS_COLLECTION,
 }
 
 func (store GameStore) getGame(ctx context.Context, id string) Game {
 	var game Game
 	store.datastore.Get(ctx, GAMES_COLLECTION, id, &game)
+	MigrateRoster(&game)
 	return game
 }
 
@@ -39,6 +49,69 @@ func FixupEventIds(game *Game) {
 	}
 }
 
+var errGameNotFound = errors.New("game not found")
+
+// Returned by a change to leave an item as it was without it being treated as a failure.
+var errUnchanged = errors.New("unchanged")
+
+// Changes a game in a transaction, so that changes made to the same game at the same time,
+// including on other servers, are not lost. The change may be run more than once if the game
+// is changed by someone else before the transaction completes. If the change returns an error,
+// or the game does not exist, the game is left as it was and the error is returned.
+func (store GameStore) updateGame(ctx context.Context, id string, change func(game *Game) error) (Game, error) {
+	var updated Game
+	err := store.datastore.Update(ctx, GAMES_COLLECTION, id, func() interface{} { return new(Game) }, func(item interface{}) error {
+		game := item.(*Game)
+		if game.ID != id {
+			return errGameNotFound
+		}
+		MigrateRoster(game)
+		if err := change(game); err != nil {
+			return err
+		}
+		FixupEventIds(game)
+		updated = *game
+		return nil
+	})
+	return updated, err
+}
+
+// Adds an event to a game unless it has already been added, which happens when a form is
+// submitted twice or a request is retried, or the event has since been deleted. The check and
+// the update are made in one transaction, so that only one copy of an event is ever added,
+// and never to a game that has been locked or finalised in the meantime.
+// Returns the event in the game (or recycle bin) and whether it was added, or an ErrorCode if
+// the game can't be changed.
+func (store GameStore) addEvent(ctx context.Context, gameId string, event Event) (Event, bool, error) {
+	deleted := store.getDeleted(ctx, "event", eventCode(gameId, event.ID))
+	if deleted.Data != "" {
+		var existing Event
+		if err := json.Unmarshal([]byte(deleted.Data), &existing); err == nil {
+			return existing, false, nil
+		}
+	}
+
+	var existing Event
+	added := false
+	_, err := store.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		if game.IsFinal() {
+			return ErrorCode("8005")
+		}
+		existing, added = AddEventOnce(game, event)
+		if !added {
+			return errUnchanged
+		}
+		return nil
+	})
+	if err == errUnchanged {
+		return existing, false, nil
+	}
+	return existing, added, err
+}
+
 func (store GameStore) addGame(ctx context.Context, game Game) string {
 	game.ID = store.getUniqueCode(ctx, GAMES_COLLECTION)
 	store.putGame(ctx, game.ID, game)
@@ -61,15 +134,63 @@ func (store GameStore) addList(ctx context.Context, list GameList) string {
 	return list.ID
 }
 
-func (store GameStore) deleteItem(ctx context.Context, itemType string, id string) {
-	store.datastore.Delete(ctx, Collections[itemType], id)
+func (store GameStore) putTeam(ctx context.Context, id string, team Team) {
+	store.datastore.Put(ctx, TEAMS_COLLECTION, id, team)
+}
+
+func (store GameStore) getTeam(ctx context.Context, id string) Team {
+	var team Team
+	store.datastore.Get(ctx, TEAMS_COLLECTION, id, &team)
+	return team
+}
+
+func (store GameStore) addTeam(ctx context.Context, team Team) string {
+	team.ID = store.getUniqueCode(ctx, TEAMS_COLLECTION)
+	store.putTeam(ctx, team.ID, team)
+	return team.ID
+}
+
+// Each change to a game is kept as its own document in a collection for the game, so that
+// changes made at the same time are not lost and the log can grow without limit.
+func changeEntriesCollection(gameId string) string {
+	return CHANGES_COLLECTION + "/" + gameId + "/" + CHANGE_ENTRIES
+}
+
+func (store GameStore) getChangeLog(ctx context.Context, gameId string) ChangeLog {
+	log := ChangeLog{GameID: gameId}
+
+	keys := store.datastore.Keys(ctx, changeEntriesCollection(gameId))
+	sort.Strings(keys)
+	for _, key := range keys {
+		var change GameChange
+		store.datastore.Get(ctx, changeEntriesCollection(gameId), key, &change)
+		log.Changes = append(log.Changes, change)
+	}
+	return log
+}
+
+// Appends a change to the log kept for a game. The key starts with the time of the change
+// so that the entries sort in the order they were made.
+func (store GameStore) recordChange(ctx context.Context, gameId string, change GameChange) {
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+	key := change.Timestamp.UTC().Format("20060102T150405.000000000") + "-" + randomEventId()
+	store.datastore.Put(ctx, changeEntriesCollection(gameId), key, change)
 }
 
-// Returns a code that is unique as an identifier within the specified collection.
+// Returns a code that is unique as an identifier within the specified collection,
+// including any items from that collection that are in the recycle bin.
 func (store GameStore) getUniqueCode(ctx context.Context, collection string) string {
+	itemType := ""
+	for name, value := range Collections {
+		if value == collection {
+			itemType = name
+		}
+	}
 	for {
 		id := randomId()
-		if !store.datastore.Exists(ctx, collection, id) {
+		if !store.datastore.Exists(ctx, collection, id) && !store.datastore.Exists(ctx, DELETED_COLLECTION, deletedKey(itemType, id)) {
 			return id
 		}
 	}
@@ -91,18 +212,28 @@ func (store GameStore) close() {
 	store.datastore.close()
 }
 
+func (store GameStore) ping(ctx context.Context) error {
+	return store.datastore.ping(ctx)
+}
+
 type DataStore interface {
 	summary() string
 	open()
 	close()
+	ping(ctx context.Context) error
 	Get(ctx context.Context, collection string, id string, item interface{}) interface{}
 	Put(ctx context.Context, collection string, id string, item interface{})
 	Delete(ctx context.Context, collection string, id string)
 	Exists(ctx context.Context, collection string, id string) bool
+	Keys(ctx context.Context, collection string) []string
+	// Reads an item into a new value from newItem, passes it to change and, unless change
+	// returns an error, writes it back, all as one transaction.
+	Update(ctx context.Context, collection string, id string, newItem func() interface{}, change func(item interface{}) error) error
 	isEmpty() bool
 }
 
 type TestDataStore struct {
+	mutex sync.RWMutex
 	items map[string](map[string][]byte)
 }
 
@@ -112,6 +243,10 @@ func testDataStore() DataStore {
 	store.items = make(map[string]map[string][]byte)
 	store.items[GAMES_COLLECTION] = make(map[string][]byte)
 	store.items[LISTS_COLLECTION] = make(map[string][]byte)
+	store.items[TEAMS_COLLECTION] = make(map[string][]byte)
+	store.items[CHANGES_COLLECTION] = make(map[string][]byte)
+	store.items[DELETED_COLLECTION] = make(map[string][]byte)
+	store.items[DELETED_EVENTS_COLLECTION] = make(map[string][]byte)
 	return store
 }
 
@@ -120,6 +255,8 @@ func (store *TestDataStore) summary() string {
 }
 
 func (store *TestDataStore) Get(ctx context.Context, collection string, id string, item interface{}) interface{} {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
 	data := store.items[collection][id]
 
 	_ = json.Unmarshal(data, item)
@@ -127,21 +264,76 @@ func (store *TestDataStore) Get(ctx context.Context, collection string, id strin
 }
 
 func (store *TestDataStore) Exists(ctx context.Context, collection string, id string) bool {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
 	_, found := store.items[collection][id]
 	return found
 }
 
 func (store *TestDataStore) Put(ctx context.Context, collection string, id string, item interface{}) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.put(collection, id, item)
+}
+
+func (store *TestDataStore) put(collection string, id string, item interface{}) {
 	data, _ := json.Marshal(item)
+	if store.items[collection] == nil {
+		store.items[collection] = make(map[string][]byte)
+	}
 	store.items[collection][id] = data
 }
 
+func (store *TestDataStore) Keys(ctx context.Context, collection string) []string {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	keys := make([]string, 0, len(store.items[collection]))
+	for key := range store.items[collection] {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 func (store *TestDataStore) Delete(ctx context.Context, collection string, id string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
 	delete(store.items[collection], id)
 }
 
+// Changes are made without holding the lock, and retried if the item was changed by someone
+// else in the meantime, in the same way as a Firestore transaction.
+func (store *TestDataStore) Update(ctx context.Context, collection string, id string, newItem func() interface{}, change func(item interface{}) error) error {
+	for {
+		store.mutex.RLock()
+		data, found := store.items[collection][id]
+		store.mutex.RUnlock()
+
+		item := newItem()
+		if found {
+			if err := json.Unmarshal(data, item); err != nil {
+				return err
+			}
+		}
+		if err := change(item); err != nil {
+			return err
+		}
+
+		store.mutex.Lock()
+		current, stillFound := store.items[collection][id]
+		if found == stillFound && bytes.Equal(data, current) {
+			store.put(collection, id, item)
+			store.mutex.Unlock()
+			return nil
+		}
+		store.mutex.Unlock()
+	}
+}
+
 func (store *TestDataStore) open()  {}
 func (store *TestDataStore) close() {}
+func (store *TestDataStore) ping(ctx context.Context) error {
+	return nil
+}
 func (store *TestDataStore) isEmpty() bool {
 	return len(store.items[GAMES_COLLECTION]) == 0
 }
@@ -160,6 +352,7 @@ func randomId() string {
 const TEST_ID_1 = "GAME-0001"
 const TEST_ID_2 = "GAME-0002"
 const TEST_LIST_ID = "LIST-0001"
+const TEST_TEAM_ID = "TEAM-0001"
 
 func testGame1() Game {
 	game1 := Game{
@@ -170,6 +363,7 @@ func testGame1() Game {
 		AwayTeam: "Blues",
 		GameDate: "2024-05-27",
 	}
+	CopyRoster(&game1, HOME, testTeam1())
 	AddPenalty(&game1, 2, "14:25", AWAY, 50, 2, "Slash")
 	AddGoal(&game1, 1, "18:30", HOME, 41, 89, 93, "Even")
 	AddPenalty(&game1, 2, "3:45", HOME, 41, 2, "Trip")
@@ -188,10 +382,21 @@ func testGame2() Game {
 		LockedWith: "secret123",
 	}
 	AddGoal(&game2, 1, "18:30", HOME, 41, 89, 93, "Even")
+	game2.Status = STATUS_FINAL
 
 	return game2
 }
 
+func testTeam1() Team {
+	team := NewTeam("Reds")
+	team.ID = TEST_TEAM_ID
+	team.AddPlayer(Player{Number: 41, Name: "Smith, J", Position: "C", Shoots: "L", Role: CAPTAIN})
+	team.AddPlayer(Player{Number: 89, Name: "Jones, A", Position: "LW", Shoots: "R", Role: ALTERNATE})
+	team.AddPlayer(Player{Number: 93, Name: "Brown, K", Position: "D", Shoots: "L"})
+	team.AddPlayer(Player{Number: 1, Name: "Green, T", Position: "G", Shoots: "L"})
+	return team
+}
+
 func addTestGames(store GameStore) {
 	logs.info("Adding test games to %s", store.summary())
 
@@ -208,5 +413,8 @@ func addTestGames(store GameStore) {
 	list1.AddGame(TEST_ID_2)
 	store.putList(ctx, TEST_LIST_ID, list1)
 
+	team1 := testTeam1()
+	store.putTeam(ctx, team1.ID, team1)
+
 	logs.info("Test games added")
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -47,3 +49,120 @@ func TestManyRandomIds(t *testing.T) {
 		}
 	}
 }
+
+func TestAddEventOnce(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	ctx := context.Background()
+	store.putGame(ctx, "CODE1", Game{ID: "CODE1"})
+
+	first, added, _ := store.addEvent(ctx, "CODE1", Event{ID: "EV1", EventType: GOAL, Player: 41})
+	if !added || first.Player != 41 {
+		t.Errorf("Expected event to be added: %+v", first)
+	}
+
+	existing, added, _ := store.addEvent(ctx, "CODE1", Event{ID: "EV1", EventType: GOAL, Player: 99})
+	if added {
+		t.Error("Duplicate event should not be added")
+	}
+	if existing.Player != 41 {
+		t.Errorf("Expected the existing event to be returned, got %+v", existing)
+	}
+	if game := store.getGame(ctx, "CODE1"); len(game.Events) != 1 {
+		t.Errorf("Expected one event, got %d", len(game.Events))
+	}
+}
+
+func TestAddEventConcurrently(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	ctx := context.Background()
+	store.putGame(ctx, "CODE1", Game{ID: "CODE1"})
+
+	var wg sync.WaitGroup
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.addEvent(ctx, "CODE1", Event{ID: "EV1", EventType: GOAL})
+		}()
+	}
+	wg.Wait()
+
+	if game := store.getGame(ctx, "CODE1"); len(game.Events) != 1 {
+		t.Errorf("Expected one event, got %d", len(game.Events))
+	}
+}
+
+func TestAddEventToLockedOrFinalGame(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	ctx := context.Background()
+	store.putGame(ctx, "LOCKED", Game{ID: "LOCKED", LockedWith: "key"})
+	store.putGame(ctx, "FINAL", Game{ID: "FINAL", Status: STATUS_FINAL})
+
+	for gameId, expected := range map[string]ErrorCode{"LOCKED": "8001", "FINAL": "8005"} {
+		_, added, err := store.addEvent(ctx, gameId, Event{ID: "EV1", EventType: GOAL})
+		if added || err != expected {
+			t.Errorf("Expected %s adding to %s, got %v", expected, gameId, err)
+		}
+		if game := store.getGame(ctx, gameId); len(game.Events) != 0 {
+			t.Errorf("Event should not be added to %s", gameId)
+		}
+	}
+}
+
+func TestUpdateGameKeepsConcurrentChanges(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	ctx := context.Background()
+	store.putGame(ctx, "CODE1", Game{ID: "CODE1"})
+
+	var wg sync.WaitGroup
+	for n := 0; n < 10; n++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			store.addEvent(ctx, "CODE1", Event{ID: fmt.Sprintf("EV%d", n), EventType: GOAL})
+		}(n)
+		go func(n int) {
+			defer wg.Done()
+			store.updateGame(ctx, "CODE1", func(game *Game) error {
+				game.HomeRoster = append(game.HomeRoster, Player{Number: n})
+				return nil
+			})
+		}(n)
+	}
+	wg.Wait()
+
+	game := store.getGame(ctx, "CODE1")
+	if len(game.Events) != 10 {
+		t.Errorf("Expected 10 events, got %d", len(game.Events))
+	}
+	if len(game.HomeRoster) != 10 {
+		t.Errorf("Expected 10 players, got %d", len(game.HomeRoster))
+	}
+}
+
+func TestUpdateUnknownGame(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	_, err := store.updateGame(context.Background(), "NONE", func(game *Game) error { return nil })
+	if err != errGameNotFound {
+		t.Errorf("Expected game not found, got %v", err)
+	}
+}
+
+func TestAddDeletedEvent(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	ctx := context.Background()
+	store.putGame(ctx, "CODE1", Game{ID: "CODE1"})
+	store.addEvent(ctx, "CODE1", Event{ID: "EV1", EventType: GOAL, Player: 41})
+
+	game := store.getGame(ctx, "CODE1")
+	store.deleteEvent(ctx, &game, "EV1")
+	store.putGame(ctx, "CODE1", game)
+
+	existing, added, _ := store.addEvent(ctx, "CODE1", Event{ID: "EV1", EventType: GOAL, Player: 41})
+	if added || existing.ID != "EV1" {
+		t.Errorf("Retried event should not come back after being deleted: %+v", existing)
+	}
+	if game := store.getGame(ctx, "CODE1"); len(game.Events) != 0 {
+		t.Errorf("Expected no events, got %d", len(game.Events))
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Returns the type of a penalty. Penalties recorded before the penalty catalogue was
+// introduced have their type worked out from the minutes given.
+func PenaltyType(event Event) string {
+	if event.PenaltyType != "" {
+		return event.PenaltyType
+	}
+	for _, penaltyType := range PenaltyTypes {
+		if PenaltyTypeMinutes[penaltyType] == event.Minutes {
+			return penaltyType
+		}
+	}
+	return PENALTY_MINOR
+}
+
+// The suspensions that a league gives for penalties. A player is suspended for a number of
+// games after a game misconduct or match penalty, and again each time their penalty minutes
+// across the list pass another multiple of the PIM threshold.
+type DisciplineRules struct {
+	GameMisconductGames int
+	MatchPenaltyGames   int
+	PIMThreshold        int
+	PIMThresholdGames   int
+}
+
+var DefaultDisciplineRules = DisciplineRules{
+	GameMisconductGames: 1,
+	MatchPenaltyGames:   3,
+	PIMThreshold:        50,
+	PIMThresholdGames:   1,
+}
+
+// A DisciplineIncident is a penalty, or build up of penalty minutes, that led to a suspension.
+type DisciplineIncident struct {
+	GameID    string
+	GameTitle string
+	GameDate  string
+	Reason    string
+	Games     int
+}
+
+// The disciplinary record of one player across the games in a list.
+type DisciplineRecord struct {
+	Key            string
+	Team           string
+	Number         int
+	Name           string
+	PIM            int
+	GamesSuspended int
+	Incidents      []DisciplineIncident
+}
+
+// Builds the disciplinary record of every penalised player across a set of games, with the
+// suspensions given by the rules. Games are taken in date order so that PIM thresholds are
+// reached in the right game. Players with suspensions are listed first.
+func Discipline(games []Game, rules DisciplineRules) []DisciplineRecord {
+	ordered := make([]Game, len(games))
+	copy(ordered, games)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].GameDate < ordered[j].GameDate
+	})
+
+	records := make(map[string]*DisciplineRecord)
+	for _, game := range ordered {
+		SortEvents(&game)
+		for _, event := range game.Events {
+			if event.EventType != PENALTY || event.Player == 0 || game.Roster(event.HomeAway) == nil {
+				continue
+			}
+			record := disciplineRecord(records, game, event)
+			addPenaltyToRecord(record, game, event, rules)
+		}
+	}
+
+	list := make([]DisciplineRecord, 0, len(records))
+	for _, record := range records {
+		list = append(list, *record)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.GamesSuspended != b.GamesSuspended {
+			return a.GamesSuspended > b.GamesSuspended
+		}
+		if a.PIM != b.PIM {
+			return a.PIM > b.PIM
+		}
+		if a.Team != b.Team {
+			return a.Team < b.Team
+		}
+		return a.Number < b.Number
+	})
+	return list
+}
+
+func disciplineRecord(records map[string]*DisciplineRecord, game Game, event Event) *DisciplineRecord {
+	team := game.HomeTeam
+	if event.HomeAway == AWAY {
+		team = game.AwayTeam
+	}
+	roster := *game.Roster(event.HomeAway)
+
+	key := playerStatsKey(team, roster, event.Player)
+	record, ok := records[key]
+	if !ok {
+		record = &DisciplineRecord{Key: key, Team: team, Number: event.Player}
+		records[key] = record
+	}
+	if player := FindPlayer(roster, event.Player); player != nil && player.Name != "" {
+		record.Name = player.Name
+	}
+	return record
+}
+
+func addPenaltyToRecord(record *DisciplineRecord, game Game, event Event, rules DisciplineRules) {
+	incident := DisciplineIncident{
+		GameID:    game.ID,
+		GameTitle: game.Title,
+		GameDate:  game.GameDate,
+	}
+
+	before := record.PIM
+	record.PIM += event.Minutes
+
+	for _, part := range PenaltyParts(event) {
+		switch part {
+		case PENALTY_GAME_MISCONDUCT:
+			addIncident(record, incident, fmt.Sprintf("Game misconduct (%s)", event.Category), rules.GameMisconductGames)
+		case PENALTY_MATCH:
+			addIncident(record, incident, fmt.Sprintf("Match penalty (%s)", event.Category), rules.MatchPenaltyGames)
+		}
+	}
+
+	if rules.PIMThreshold > 0 && record.PIM/rules.PIMThreshold > before/rules.PIMThreshold {
+		reached := (record.PIM / rules.PIMThreshold) * rules.PIMThreshold
+		addIncident(record, incident, fmt.Sprintf("Reached %d penalty minutes", reached), rules.PIMThresholdGames)
+	}
+}
+
+func addIncident(record *DisciplineRecord, incident DisciplineIncident, reason string, games int) {
+	incident.Reason = reason
+	incident.Games = games
+	record.Incidents = append(record.Incidents, incident)
+	record.GamesSuspended += games
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestPenaltyType(t *testing.T) {
+	types := map[int]string{2: PENALTY_MINOR, 4: PENALTY_DOUBLE_MINOR, 5: PENALTY_MAJOR, 10: PENALTY_MISCONDUCT, 20: PENALTY_GAME_MISCONDUCT, 25: PENALTY_MATCH}
+	for minutes, penaltyType := range types {
+		if PenaltyType(Event{Minutes: minutes}) != penaltyType {
+			t.Errorf("Expected %s for %d minutes, got %s", penaltyType, minutes, PenaltyType(Event{Minutes: minutes}))
+		}
+	}
+}
+
+func TestDisciplineSuspensions(t *testing.T) {
+	game1 := testGame1()
+	game2 := game1
+	game2.Events = append([]Event{}, game1.Events...)
+	game2.ID = "GAME-0002"
+	game2.GameDate = "2024-05-20"
+	AddPenalty(&game1, 3, "05:00", HOME, 41, 25, "Spearing")
+	AddPenalty(&game2, 1, "05:00", HOME, 41, 20, "Fighting")
+
+	records := Discipline([]Game{game1, game2}, DefaultDisciplineRules)
+
+	if len(records) != 2 {
+		t.Fatalf("Unexpected discipline records: %+v", records)
+	}
+	record := records[0]
+	if record.Number != 41 || record.Name != "Smith, J" || record.PIM != 49 {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if record.GamesSuspended != 4 || len(record.Incidents) != 2 {
+		t.Errorf("Expected game misconduct and match penalty suspensions: %+v", record.Incidents)
+	}
+	if record.Incidents[0].GameID != "GAME-0002" {
+		t.Errorf("Incidents not in date order: %+v", record.Incidents)
+	}
+}
+
+func TestDisciplinePIMThreshold(t *testing.T) {
+	game := testGame1()
+	rules := DisciplineRules{PIMThreshold: 6, PIMThresholdGames: 2}
+	AddPenalty(&game, 3, "05:00", HOME, 41, 2, "Hooking")
+	AddPenalty(&game, 3, "04:00", HOME, 41, 2, "Hooking")
+
+	records := Discipline([]Game{game}, rules)
+
+	if records[0].GamesSuspended != 2 || records[0].Incidents[0].Reason != "Reached 6 penalty minutes" {
+		t.Errorf("PIM threshold not applied: %+v", records[0])
+	}
+}
+
+func TestListDisciplineRules(t *testing.T) {
+	var list GameList
+	if list.DisciplineRules() != DefaultDisciplineRules {
+		t.Error("Expected default rules for a new list")
+	}
+
+	list.Discipline = &DisciplineRules{MatchPenaltyGames: 5}
+	if list.DisciplineRules().MatchPenaltyGames != 5 {
+		t.Error("List rules not used")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Writes the full record of a game as CSV, in sections for the game details,
+// the team rosters and the game events.
+func WriteGameCsv(w io.Writer, game Game) error {
+	out := csv.NewWriter(w)
+
+	out.Write([]string{"Game", game.ID})
+	out.Write([]string{"Title", game.Title})
+	out.Write([]string{"Date", game.GameDate})
+	out.Write([]string{"Home", game.HomeTeam})
+	out.Write([]string{"Away", game.AwayTeam})
+	out.Write([]string{"Status", game.CurrentStatus()})
+	out.Write([]string{})
+
+	out.Write([]string{"Referees", game.Officials.Referee1, game.Officials.Referee2})
+	out.Write([]string{"Linesmen", game.Officials.Linesman1, game.Officials.Linesman2})
+	out.Write([]string{"Scorekeeper", game.Officials.Scorekeeper})
+	out.Write([]string{"Timekeeper", game.Officials.Timekeeper})
+	out.Write([]string{"Home staff", game.HomeStaff.Coach, game.HomeStaff.Manager})
+	out.Write([]string{"Away staff", game.AwayStaff.Coach, game.AwayStaff.Manager})
+	out.Write([]string{})
+
+	out.Write([]string{"Team", "Number", "Name", "Position", "C/A", "Starting Goalie", "Status"})
+	writeRosterCsv(out, HOME, game.HomeRoster)
+	writeRosterCsv(out, AWAY, game.AwayRoster)
+	out.Write([]string{})
+
+	out.Write([]string{"Period", "Clock Time", "Game Time", "Team", "Event", "Category", "Player", "Assist 1", "Assist 2", "Minutes", "Empty Net", "Outcome", "Penalty Event", "Home On Ice", "Away On Ice", "Zone", "Opponent", "Penalty Type", "Coincidental"})
+	emptyNet := EmptyNetGoals(game)
+	coincidental := CoincidentalPenalties(game)
+	for _, event := range game.Events {
+		out.Write([]string{
+			strconv.Itoa(event.Period),
+			string(event.ClockTime),
+			string(event.GameTime),
+			event.HomeAway,
+			event.EventType,
+			event.Category,
+			csvNumber(event.Player),
+			csvNumber(event.Assist1),
+			csvNumber(event.Assist2),
+			csvNumber(event.Minutes),
+			csvFlag(emptyNet[event.ID], EMPTY_NET),
+			event.Outcome,
+			event.PenaltyEventID,
+			csvNumbers(event.HomeOnIce),
+			csvNumbers(event.AwayOnIce),
+			event.Zone,
+			csvNumber(event.Opponent),
+			penaltyTypeCsv(event),
+			csvFlag(coincidental[event.ID], "Y"),
+		})
+	}
+
+	if len(game.Signatures) > 0 {
+		out.Write([]string{})
+		out.Write([]string{"Signed by", "Name", "Signed"})
+		for _, signature := range game.Signatures {
+			out.Write([]string{signature.Role, signature.Name, signature.Signed.Format(time.RFC3339)})
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func writeRosterCsv(out *csv.Writer, homeAway string, roster []Player) {
+	for _, player := range roster {
+		goalie := ""
+		if player.StartingGoalie {
+			goalie = "Y"
+		}
+		out.Write([]string{
+			homeAway,
+			strconv.Itoa(player.Number),
+			player.Name,
+			player.Position,
+			player.Role,
+			goalie,
+			player.Status(),
+		})
+	}
+}
+
+// Describes all the parts of a penalty for CSV output, leaving other events blank.
+func penaltyTypeCsv(event Event) string {
+	if event.PenaltyType == "" {
+		return ""
+	}
+	return event.PenaltyDescription()
+}
+
+// Returns the value if the flag is set, otherwise leaves the CSV field blank.
+func csvFlag(set bool, value string) string {
+	if set {
+		return value
+	}
+	return ""
+}
+
+// Formats a list of numbers for CSV output, separated by spaces.
+func csvNumbers(numbers []int) string {
+	values := make([]string, len(numbers))
+	for n, number := range numbers {
+		values[n] = strconv.Itoa(number)
+	}
+	return strings.Join(values, " ")
+}
+
+// Formats a number for CSV output, leaving zero values blank.
+func csvNumber(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+// Writes season player statistics as CSV, one row per player.
+func WriteStatsCsv(w io.Writer, teams []TeamStats) error {
+	out := csv.NewWriter(w)
+
+	out.Write([]string{"Team", "Number", "Name", "GP", "G", "A", "PTS", "PIM", "PPG", "SHG", "+/-", "FOW", "FOL"})
+	for _, team := range teams {
+		for _, player := range team.Players {
+			out.Write([]string{
+				team.Team,
+				strconv.Itoa(player.Number),
+				player.Name,
+				strconv.Itoa(player.GamesPlayed),
+				strconv.Itoa(player.Goals),
+				strconv.Itoa(player.Assists),
+				strconv.Itoa(player.Points),
+				strconv.Itoa(player.Minutes),
+				strconv.Itoa(player.PowerPlayGoals),
+				strconv.Itoa(player.ShortHandedGoals),
+				strconv.Itoa(player.PlusMinus),
+				strconv.Itoa(player.Faceoffs.Won),
+				strconv.Itoa(player.Faceoffs.Lost),
+			})
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
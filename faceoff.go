@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+)
+
+const FACEOFF = "Faceoff"
+
+// Faceoff zones are recorded relative to the team that won the faceoff.
+const ZONE_OFFENSIVE = "OZ"
+const ZONE_NEUTRAL = "NZ"
+const ZONE_DEFENSIVE = "DZ"
+
+// Faceoff totals for a player or team.
+type FaceoffStats struct {
+	Won  int
+	Lost int
+}
+
+func (stats FaceoffStats) Taken() int {
+	return stats.Won + stats.Lost
+}
+
+// Returns the percentage of faceoffs won, or a dash if none have been taken.
+func (stats FaceoffStats) Percentage() string {
+	if stats.Taken() == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", 100*float64(stats.Won)/float64(stats.Taken()))
+}
+
+// Adds a faceoff to the summary. The event is recorded against the winning team, with the
+// winning centre as the player and the losing centre as the opponent.
+func countFaceoff(summary *GameSummary, event Event) {
+	winners, losers := summary.HomePlayers, summary.AwayPlayers
+	won, lost := &summary.HomeFaceoffs, &summary.AwayFaceoffs
+	if event.HomeAway == AWAY {
+		winners, losers = summary.AwayPlayers, summary.HomePlayers
+		won, lost = &summary.AwayFaceoffs, &summary.HomeFaceoffs
+	}
+
+	won.Won++
+	lost.Lost++
+
+	if event.Player > 0 {
+		player := winners[event.Player]
+		player.Faceoffs.Won++
+		winners[event.Player] = player
+	}
+	if event.Opponent > 0 {
+		player := losers[event.Opponent]
+		player.Faceoffs.Lost++
+		losers[event.Opponent] = player
+	}
+}
+
+// Converts the end of the rink where a faceoff was taken, "Home", "Away" or "Neutral",
+// into the zone for the team that won it. Returns "" for any other end.
+func faceoffZone(end string, winner string) string {
+	switch end {
+	case winner:
+		return ZONE_DEFENSIVE
+	case opposingTeam(winner):
+		return ZONE_OFFENSIVE
+	case "Neutral":
+		return ZONE_NEUTRAL
+	}
+	return ""
+}
+
+// Returns the centres that took the most recent faceoff, so that they can be offered for
+// the next one.
+func LastCentres(game Game) (home int, away int) {
+	var last Event
+	for _, event := range game.Events {
+		if event.EventType == FACEOFF && event.GameTime >= last.GameTime {
+			last = event
+		}
+	}
+	if last.HomeAway == HOME {
+		return last.Player, last.Opponent
+	}
+	return last.Opponent, last.Player
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+)
+
+func addFaceoff(game *Game, period int, clockTime EventTime, winner string, player int, opponent int) {
+	AddEvent(game, Event{
+		ID:        randomEventId(),
+		Period:    period,
+		ClockTime: clockTime,
+		GameTime:  ClockToGameTime(period, clockTime),
+		EventType: FACEOFF,
+		HomeAway:  winner,
+		Player:    player,
+		Opponent:  opponent,
+	})
+}
+
+func TestFaceoffsInSummary(t *testing.T) {
+	game := testGame1()
+	addFaceoff(&game, 1, "20:00", HOME, 41, 98)
+	addFaceoff(&game, 1, "15:00", HOME, 41, 98)
+	addFaceoff(&game, 1, "10:00", AWAY, 98, 41)
+
+	summary := summarise(game)
+
+	if summary.HomeFaceoffs.Won != 2 || summary.HomeFaceoffs.Lost != 1 || summary.AwayFaceoffs.Won != 1 {
+		t.Errorf("Unexpected team faceoffs: home %+v, away %+v", summary.HomeFaceoffs, summary.AwayFaceoffs)
+	}
+	centre := summary.HomePlayers[41].Faceoffs
+	if centre.Won != 2 || centre.Lost != 1 || centre.Percentage() != "66.7" {
+		t.Errorf("Unexpected faceoffs for #41: %+v %s", centre, centre.Percentage())
+	}
+	if summary.HomePlayers[41].Goals != 1 {
+		t.Error("Faceoffs changed goals for #41")
+	}
+}
+
+func TestFaceoffPercentageWithNoFaceoffs(t *testing.T) {
+	if (FaceoffStats{}).Percentage() != "-" {
+		t.Error("Expected a dash when no faceoffs have been taken")
+	}
+}
+
+func TestFaceoffZone(t *testing.T) {
+	if faceoffZone(HOME, HOME) != ZONE_DEFENSIVE || faceoffZone(AWAY, HOME) != ZONE_OFFENSIVE || faceoffZone("Neutral", AWAY) != ZONE_NEUTRAL {
+		t.Error("Unexpected faceoff zones")
+	}
+}
+
+func TestLastCentres(t *testing.T) {
+	game := testGame1()
+	addFaceoff(&game, 1, "20:00", HOME, 41, 98)
+	addFaceoff(&game, 1, "15:00", AWAY, 97, 89)
+
+	home, away := LastCentres(game)
+	if home != 89 || away != 97 {
+		t.Errorf("Unexpected last centres: %d, %d", home, away)
+	}
+}
+
+func TestFaceoffSeasonStats(t *testing.T) {
+	game := testGame1()
+	addFaceoff(&game, 1, "20:00", HOME, 41, 98)
+
+	for _, team := range SeasonStatistics([]Game{game, game}) {
+		for _, player := range team.Players {
+			if player.Number == 41 && player.Faceoffs.Won != 2 {
+				t.Errorf("Unexpected season faceoffs for #41: %+v", player.Faceoffs)
+			}
+		}
+	}
+}
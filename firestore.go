@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"cloud.google.com/go/firestore"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type FireDataStore struct {
@@ -44,10 +47,38 @@ func (store *FireDataStore) open() {
 }
 
 func (store *FireDataStore) close() {
-	store.Client.Close()
+	if store.Client != nil {
+		store.Client.Close()
+	}
+}
+
+// Checks the client was created and can read from the games collection.
+func (store *FireDataStore) ping(ctx context.Context) error {
+	if store.Err != nil {
+		return store.Err
+	}
+	if store.Client == nil {
+		return errors.New("firestore client not open")
+	}
+	_, err := store.Client.Collection(GAMES_COLLECTION).Limit(1).Documents(ctx).Next()
+	if err != nil && err != iterator.Done {
+		recordFirestoreError("ping", GAMES_COLLECTION, err)
+		return err
+	}
+	return nil
+}
+
+// Counts failed operations for the metrics page. A missing document is not a failure.
+func recordFirestoreError(operation string, collection string, err error) {
+	if status.Code(err) != codes.NotFound {
+		metrics.recordDatastoreError(operation, collection)
+	}
 }
 
 func (store *FireDataStore) isEmpty() bool {
+	if store.Client == nil {
+		return false
+	}
 	games := store.Client.Collection(GAMES_COLLECTION)
 	_, err := games.Documents(context.Background()).Next()
 	return err == iterator.Done
@@ -59,6 +90,7 @@ func (store *FireDataStore) Put(ctx context.Context, collection string, id strin
 	doc := store.Client.Doc(collection + "/" + id)
 	_, err := doc.Set(ctx, item)
 	if err != nil {
+		recordFirestoreError("put", collection, err)
 		logs.error1(ctx, "Error writing item %v", err)
 	} else {
 		logs.debug1(ctx, "Wrote iten %s", id)
@@ -71,6 +103,7 @@ func (store *FireDataStore) Get(ctx context.Context, collection string, id strin
 	doc := store.Client.Doc(collection + "/" + id)
 	data, err := doc.Get(ctx)
 	if err != nil {
+		recordFirestoreError("get", collection, err)
 		logs.error1(ctx, "Error fetching game %s, %v", id, err)
 	} else {
 		logs.debug1(ctx, "Found game document %s", id)
@@ -83,9 +116,59 @@ func (store *FireDataStore) Get(ctx context.Context, collection string, id strin
 
 func (store FireDataStore) Exists(ctx context.Context, collection string, id string) bool {
 	doc, err := store.Client.Doc(collection + "/" + id).Get(ctx)
+	if err != nil {
+		recordFirestoreError("exists", collection, err)
+	}
 	return err == nil && doc.Exists()
 }
 
 func (store FireDataStore) Delete(ctx context.Context, collection string, id string) {
-	store.Client.Doc(collection + "/" + id).Delete(ctx)
+	_, err := store.Client.Doc(collection + "/" + id).Delete(ctx)
+	if err != nil {
+		recordFirestoreError("delete", collection, err)
+		logs.error1(ctx, "Error deleting %s %s: %v", collection, id, err)
+	}
+}
+
+func (store FireDataStore) Update(ctx context.Context, collection string, id string, newItem func() interface{}, change func(item interface{}) error) error {
+	doc := store.Client.Doc(collection + "/" + id)
+	var changeErr error
+	err := store.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		item := newItem()
+		snapshot, err := tx.Get(doc)
+		if err == nil {
+			if err = snapshot.DataTo(item); err != nil {
+				return err
+			}
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+		if changeErr = change(item); changeErr != nil {
+			return changeErr
+		}
+		return tx.Set(doc, item)
+	})
+	if err != nil && err != changeErr {
+		recordFirestoreError("update", collection, err)
+		logs.error1(ctx, "Error updating %s %s: %v", collection, id, err)
+	}
+	return err
+}
+
+func (store FireDataStore) Keys(ctx context.Context, collection string) []string {
+	var keys []string
+	refs := store.Client.Collection(collection).DocumentRefs(ctx)
+	for {
+		doc, err := refs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			recordFirestoreError("keys", collection, err)
+			logs.error1(ctx, "Error listing %s: %v", collection, err)
+			break
+		}
+		keys = append(keys, doc.ID)
+	}
+	return keys
 }
@@ -17,11 +17,21 @@ type Game struct {
 	GameDate    string `form:"game_date"`
 	HomeTeam    string `form:"home_team"`
 	AwayTeam    string `form:"away_team"`
+	HomeTeamID  string `form:"home_team_id"`
+	AwayTeamID  string `form:"away_team_id"`
 	Venue       string
-	Competition string
+	Competition string `form:"competition"`
 	LockedWith  string
-	HomePlayers map[string]string
+	HomePlayers map[string]string // Legacy roster of names by number, see MigrateRoster
 	AwayPlayers map[string]string
+	HomeRoster  []Player
+	AwayRoster  []Player
+	Clock       GameClock
+	Status      string
+	Officials   Officials
+	HomeStaff   TeamStaff
+	AwayStaff   TeamStaff
+	Signatures  []Signature
 	Created     time.Time
 }
 
@@ -52,12 +62,45 @@ type Event struct {
 	Assist1   int    `form:"assist1"`
 	Assist2   int    `form:"assist2"`
 	Minutes   int    `form:"penaltyMinutes"`
+
+	PenaltyType  string             `form:"penalty_type"` // Minor, Major etc, from the penalty catalogue
+	Components   []PenaltyComponent // Extra parts of a multi-part penalty
+	Coincidental bool               `form:"coincidental"`
+
+	PenaltyEventID string `form:"penalty_event_id"` // The penalty a penalty shot was awarded for
+	Outcome        string `form:"outcome"`
+
+	HomeOnIce []int `form:"home_on_ice"` // Players on the ice when a goal was scored, for plus/minus
+	AwayOnIce []int `form:"away_on_ice"`
+
+	Zone     string `form:"zone"`     // Faceoff zone, relative to the winning team
+	Opponent int    `form:"opponent"` // The losing centre in a faceoff
 }
 
 type PlayerSummary struct {
-	Goals   int
-	Assists int
-	Minutes int
+	Label            string
+	Goals            int
+	Assists          int
+	Minutes          int
+	PowerPlayGoals   int
+	ShortHandedGoals int
+	EmptyNetGoals    int
+	PenaltyShots     int
+	PenaltyShotGoals int
+	PlusMinus        int
+	Faceoffs         FaceoffStats
+}
+
+// An EventSummary is an event with its player numbers resolved through the game roster.
+type EventSummary struct {
+	Event
+	PlayerLabel  string
+	Assist1Label string
+	Assist2Label string
+	Unrostered   []int
+	EmptyNet     bool
+	PenaltyLabel string
+	Coincidental bool
 }
 
 type PeriodSummary struct {
@@ -69,11 +112,29 @@ type PeriodSummary struct {
 }
 
 type GameSummary struct {
-	HomeGoals   int
-	AwayGoals   int
-	HomePlayers map[int]PlayerSummary
-	AwayPlayers map[int]PlayerSummary
-	Periods     []PeriodSummary
+	HomeGoals         int
+	AwayGoals         int
+	HomePlayers       map[int]PlayerSummary
+	AwayPlayers       map[int]PlayerSummary
+	Periods           []PeriodSummary
+	Events            []EventSummary
+	HomeTimeouts      int
+	AwayTimeouts      int
+	HomeEmptyNetGoals int
+	AwayEmptyNetGoals int
+
+	HomePenaltyShots     int
+	AwayPenaltyShots     int
+	HomePenaltyShotGoals int
+	AwayPenaltyShotGoals int
+
+	HomeFaceoffs FaceoffStats
+	AwayFaceoffs FaceoffStats
+
+	HomePIM        int
+	AwayPIM        int
+	HomePowerPlays int // Power play opportunities for the home team
+	AwayPowerPlays int
 }
 
 func (game Game) LinkCode() string {
@@ -84,12 +145,71 @@ func (game *Game) SetLockedWith(key string) {
 	game.LockedWith = key
 }
 
+// Returns true if the game cannot be edited, either because it has been locked with
+// a key or because both coaches have signed the scoresheet.
+func (game Game) IsLocked() bool {
+	return game.LockedWith != "" || game.SignedOff()
+}
+
 func AddEvent(game *Game, event Event) {
 	game.Events = append(game.Events, event)
 
 	if event.Period > game.Period {
 		game.Period = event.Period
 	}
+
+	UpdateStatus(game, event)
+}
+
+// Adds an event unless the game already has one with the same ID, such as when a form is
+// submitted twice or an offline event is synced again. Returns the event in the game and
+// whether it was added.
+func AddEventOnce(game *Game, event Event) (Event, bool) {
+	if existing := game.FindEvent(event.ID); existing != nil {
+		return *existing, false
+	}
+	AddEvent(game, event)
+	return event, true
+}
+
+// Removes the event with the given ID from the game, returning it and whether it was found.
+func RemoveEvent(game *Game, eventId string) (Event, bool) {
+	for n, event := range game.Events {
+		if event.ID == eventId {
+			game.Events = append(game.Events[:n], game.Events[n+1:]...)
+			return event, true
+		}
+	}
+	return Event{}, false
+}
+
+// Returns the event with the given ID, or nil if there isn't one.
+func (game *Game) FindEvent(id string) *Event {
+	if id == "" {
+		return nil
+	}
+	for n := range game.Events {
+		if game.Events[n].ID == id {
+			return &game.Events[n]
+		}
+	}
+	return nil
+}
+
+const MAX_EVENT_ID_LENGTH = 40
+
+// Returns the event ID generated by the browser, or a new random ID if there isn't a
+// sensible one.
+func clientEventId(id string) string {
+	if id == "" || len(id) > MAX_EVENT_ID_LENGTH {
+		return randomEventId()
+	}
+	for _, ch := range id {
+		if !(ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch == '-') {
+			return randomEventId()
+		}
+	}
+	return id
 }
 
 func randomEventId() string {
@@ -109,11 +229,7 @@ func AddGoal(game *Game, period int, clockTime EventTime, homeAway string, playe
 		Category:  category,
 	}
 	goal.GameTime = ClockToGameTime(period, clockTime)
-	game.Events = append(game.Events, goal)
-
-	if period > game.Period {
-		game.Period = period
-	}
+	AddEvent(game, goal)
 }
 
 func AddPenalty(game *Game, period int, clockTime EventTime, homeAway string, player int, minutes int, category string) {
@@ -128,11 +244,7 @@ func AddPenalty(game *Game, period int, clockTime EventTime, homeAway string, pl
 		Category:  category,
 	}
 	penalty.GameTime = ClockToGameTime(period, clockTime)
-	game.Events = append(game.Events, penalty)
-
-	if period > game.Period {
-		game.Period = period
-	}
+	AddEvent(game, penalty)
 }
 
 func eventTime(mins int, secs int) EventTime {
@@ -178,6 +290,13 @@ func parseEventTime(time EventTime) (int, int) {
 
 const GAME_TOTAL = 4
 
+// Periods are numbered from 1, and overtime is the last period the summary has room for.
+const LAST_PERIOD = GAME_TOTAL
+
+func ValidPeriod(period int) bool {
+	return period >= 1 && period <= LAST_PERIOD
+}
+
 func summarise(game Game) GameSummary {
 	var summary GameSummary
 
@@ -193,35 +312,131 @@ func summarise(game Game) GameSummary {
 
 	logs.debug("Summarising %d events in %s", len(game.Events), game.ID)
 
+	emptyNet := EmptyNetGoals(game)
+	coincidental := CoincidentalPenalties(game)
+
 	for _, event := range game.Events {
+		// Events outside the periods can't be counted, ValidateForFinal reports them
+		if !ValidPeriod(event.Period) {
+			continue
+		}
 		if event.EventType == GOAL && event.HomeAway == HOME {
 			summary.HomeGoals++
 			summary.Periods[event.Period-1].HomeGoals++
 			summary.Periods[GAME_TOTAL].HomeGoals++
 			countPlayerEvent(event.Player, summary.HomePlayers, 1, 0, 0)
+			countGoalCategory(event, summary.HomePlayers)
 			countAssists(event, summary.HomePlayers)
+			if emptyNet[event.ID] {
+				summary.HomeEmptyNetGoals++
+				countEmptyNetGoal(event, summary.HomePlayers)
+			}
 		}
 		if event.EventType == GOAL && event.HomeAway == AWAY {
 			summary.AwayGoals++
 			summary.Periods[event.Period-1].AwayGoals++
 			summary.Periods[GAME_TOTAL].AwayGoals++
 			countPlayerEvent(event.Player, summary.AwayPlayers, 1, 0, 0)
+			countGoalCategory(event, summary.AwayPlayers)
 			countAssists(event, summary.AwayPlayers)
+			if emptyNet[event.ID] {
+				summary.AwayEmptyNetGoals++
+				countEmptyNetGoal(event, summary.AwayPlayers)
+			}
+		}
+		if event.EventType == GOAL {
+			countPlusMinus(&summary, game, event)
 		}
 		if event.EventType == PENALTY && event.HomeAway == HOME {
 			summary.Periods[event.Period-1].HomePenalties += event.Minutes
 			summary.Periods[GAME_TOTAL].HomePenalties += event.Minutes
+			summary.HomePIM += event.Minutes
 			countPlayerEvent(event.Player, summary.HomePlayers, 0, 0, event.Minutes)
+			if createsPowerPlay(event, coincidental) {
+				summary.AwayPowerPlays++
+			}
 		}
 		if event.EventType == PENALTY && event.HomeAway == AWAY {
 			summary.Periods[event.Period-1].AwayPenalties += event.Minutes
 			summary.Periods[GAME_TOTAL].AwayPenalties += event.Minutes
+			summary.AwayPIM += event.Minutes
 			countPlayerEvent(event.Player, summary.AwayPlayers, 0, 0, event.Minutes)
+			if createsPowerPlay(event, coincidental) {
+				summary.HomePowerPlays++
+			}
+		}
+		if event.EventType == PENALTY_SHOT {
+			countPenaltyShot(&summary, event)
 		}
+		if event.EventType == FACEOFF {
+			countFaceoff(&summary, event)
+		}
+		if event.EventType == TIMEOUT && event.HomeAway == HOME {
+			summary.HomeTimeouts++
+		}
+		if event.EventType == TIMEOUT && event.HomeAway == AWAY {
+			summary.AwayTimeouts++
+		}
+		eventSummary := summariseEvent(game, event)
+		eventSummary.EmptyNet = emptyNet[event.ID]
+		eventSummary.Coincidental = coincidental[event.ID]
+		summary.Events = append(summary.Events, eventSummary)
 	}
+
+	labelPlayers(summary.HomePlayers, game.HomeRoster)
+	labelPlayers(summary.AwayPlayers, game.AwayRoster)
+
 	return summary
 }
 
+// Returns a display label for a player number, including the player name if it is on the roster.
+func PlayerLabel(roster []Player, number int) string {
+	if number == 0 {
+		return ""
+	}
+	player := FindPlayer(roster, number)
+	if player == nil || player.Name == "" {
+		return fmt.Sprintf("#%d", number)
+	}
+	return fmt.Sprintf("#%d %s", number, player.Name)
+}
+
+func summariseEvent(game Game, event Event) EventSummary {
+	var roster []Player
+	if r := game.Roster(event.HomeAway); r != nil {
+		roster = *r
+	}
+
+	summary := EventSummary{
+		Event:        event,
+		PlayerLabel:  PlayerLabel(roster, event.Player),
+		Assist1Label: PlayerLabel(roster, event.Assist1),
+		Assist2Label: PlayerLabel(roster, event.Assist2),
+	}
+
+	if penalty := LinkedPenalty(game, event); penalty != nil {
+		summary.PenaltyLabel = DescribeEvent(*penalty)
+	}
+
+	// Only flag unknown players when a roster has actually been recorded for the team
+	if len(roster) > 0 {
+		for _, number := range []int{event.Player, event.Assist1, event.Assist2} {
+			if number > 0 && FindPlayer(roster, number) == nil {
+				summary.Unrostered = append(summary.Unrostered, number)
+			}
+		}
+	}
+
+	return summary
+}
+
+func labelPlayers(players map[int]PlayerSummary, roster []Player) {
+	for number, player := range players {
+		player.Label = PlayerLabel(roster, number)
+		players[number] = player
+	}
+}
+
 func countAssists(event Event, players map[int]PlayerSummary) {
 	if event.Assist1 > 0 {
 		countPlayerEvent(event.Assist1, players, 0, 1, 0)
@@ -231,6 +446,25 @@ func countAssists(event Event, players map[int]PlayerSummary) {
 	}
 }
 
+const POWER_PLAY = "PP"
+const SHORT_HANDED = "SH"
+
+func countGoalCategory(event Event, players map[int]PlayerSummary) {
+	player := players[event.Player]
+	if event.Category == POWER_PLAY {
+		player.PowerPlayGoals++
+	} else if event.Category == SHORT_HANDED {
+		player.ShortHandedGoals++
+	}
+	players[event.Player] = player
+}
+
+func countEmptyNetGoal(event Event, players map[int]PlayerSummary) {
+	player := players[event.Player]
+	player.EmptyNetGoals++
+	players[event.Player] = player
+}
+
 func countPlayerEvent(playerNum int, playerMap map[int]PlayerSummary, goals int, assists int, minutes int) {
 	player, ok := playerMap[playerNum]
 	if !ok {
@@ -250,21 +484,91 @@ func SortEvents(game *Game) {
 	})
 }
 
-func AddPlayer(game *Game, homeAway string, playerNum int, name string) {
-	var team *map[string]string
+// Returns the roster for one side of the game, or nil if homeAway is not valid.
+func (game *Game) Roster(homeAway string) *[]Player {
 	if homeAway == HOME {
-		if game.HomePlayers == nil {
-			game.HomePlayers = make(map[string]string)
-		}
-		team = &game.HomePlayers
+		return &game.HomeRoster
 	} else if homeAway == AWAY {
-		if game.AwayPlayers == nil {
-			game.AwayPlayers = make(map[string]string)
+		return &game.AwayRoster
+	}
+	return nil
+}
+
+// Adds a player to one side of the game, replacing any existing player with the same number.
+func AddPlayer(game *Game, homeAway string, player Player) {
+	roster := game.Roster(homeAway)
+	if roster == nil {
+		return
+	}
+	player.Name = strings.TrimSpace(player.Name)
+
+	if player.StartingGoalie {
+		for n := range *roster {
+			(*roster)[n].StartingGoalie = false
 		}
-		team = &game.AwayPlayers
-	} else {
+	}
+
+	for n, existing := range *roster {
+		if existing.Number == player.Number {
+			if player.ID == "" {
+				player.ID = existing.ID
+			}
+			(*roster)[n] = player
+			return
+		}
+	}
+
+	*roster = append(*roster, player)
+	sortPlayers(*roster)
+}
+
+func RemovePlayer(game *Game, homeAway string, playerNum int) {
+	roster := game.Roster(homeAway)
+	if roster == nil {
 		return
 	}
-	playerNumKey := fmt.Sprintf("%02d", playerNum)
-	(*team)[playerNumKey] = strings.TrimSpace(name)
+	players := make([]Player, 0, len(*roster))
+	for _, player := range *roster {
+		if player.Number != playerNum {
+			players = append(players, player)
+		}
+	}
+	*roster = players
+}
+
+func sortPlayers(players []Player) {
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Number < players[j].Number
+	})
+}
+
+// Converts the old roster maps of player names keyed by zero-padded number into
+// structured roster entries. Blank names were used to delete players, so are skipped.
+func MigrateRoster(game *Game) {
+	migrateRosterMap(game, HOME, game.HomePlayers)
+	migrateRosterMap(game, AWAY, game.AwayPlayers)
+	game.HomePlayers = nil
+	game.AwayPlayers = nil
+}
+
+func migrateRosterMap(game *Game, homeAway string, names map[string]string) {
+	for key, name := range names {
+		number, err := strconv.Atoi(key)
+		if err != nil || strings.TrimSpace(name) == "" {
+			continue
+		}
+		if FindPlayer(*game.Roster(homeAway), number) == nil {
+			AddPlayer(game, homeAway, Player{Number: number, Name: name})
+		}
+	}
+}
+
+// Returns the player with the given number from a roster, or nil if not found.
+func FindPlayer(roster []Player, number int) *Player {
+	for n := range roster {
+		if roster[n].Number == number {
+			return &roster[n]
+		}
+	}
+	return nil
 }
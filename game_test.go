@@ -120,3 +120,85 @@ func TestGameSummary(t *testing.T) {
 		t.Errorf("Unexpected home player 41 goal count: %d", ps.Goals)
 	}
 }
+
+func TestAddPlayer(t *testing.T) {
+	var game Game
+
+	AddPlayer(&game, HOME, Player{Number: 30, Name: "Green, T", StartingGoalie: true})
+	AddPlayer(&game, HOME, Player{Number: 1, Name: " Black, S ", StartingGoalie: true})
+	AddPlayer(&game, AWAY, Player{Number: 12, Name: "White, B", Scratched: true})
+
+	if len(game.HomeRoster) != 2 || game.HomeRoster[0].Number != 1 {
+		t.Errorf("Unexpected home roster: %v", game.HomeRoster)
+	}
+	if game.HomeRoster[0].Name != "Black, S" {
+		t.Errorf("Player name was not trimmed: `%s`", game.HomeRoster[0].Name)
+	}
+	if game.HomeRoster[1].StartingGoalie {
+		t.Error("Only one starting goalie should be recorded per team")
+	}
+	if game.AwayRoster[0].Status() != "Scratched" {
+		t.Errorf("Unexpected away player status: %s", game.AwayRoster[0].Status())
+	}
+
+	RemovePlayer(&game, HOME, 30)
+	if len(game.HomeRoster) != 1 {
+		t.Errorf("Player was not removed: %v", game.HomeRoster)
+	}
+}
+
+func TestMigrateRoster(t *testing.T) {
+	game := Game{
+		HomePlayers: map[string]string{"41": "Smith, J", "07": "Jones, A", "12": ""},
+	}
+
+	MigrateRoster(&game)
+
+	if game.HomePlayers != nil {
+		t.Error("Legacy roster was not cleared")
+	}
+	if len(game.HomeRoster) != 2 {
+		t.Errorf("Unexpected migrated roster size: %d", len(game.HomeRoster))
+	}
+	if game.HomeRoster[0].Number != 7 || game.HomeRoster[0].Name != "Jones, A" {
+		t.Errorf("Unexpected first migrated player: %v", game.HomeRoster[0])
+	}
+}
+
+func TestSummaryPlayerLabels(t *testing.T) {
+	game := testGame1()
+	summary := summarise(game)
+
+	if summary.HomePlayers[41].Label != "#41 Smith, J" {
+		t.Errorf("Unexpected home player label: %s", summary.HomePlayers[41].Label)
+	}
+	if summary.AwayPlayers[98].Label != "#98" {
+		t.Errorf("Unexpected away player label: %s", summary.AwayPlayers[98].Label)
+	}
+
+	for _, event := range summary.Events {
+		if event.EventType == GOAL && event.HomeAway == HOME {
+			if event.Assist2Label != "#93 Brown, K" {
+				t.Errorf("Unexpected assist label: %s", event.Assist2Label)
+			}
+			if len(event.Unrostered) != 0 {
+				t.Errorf("Rostered players flagged as unknown: %v", event.Unrostered)
+			}
+		}
+		if event.HomeAway == AWAY && len(event.Unrostered) != 0 {
+			t.Error("Players should not be flagged when the team has no roster")
+		}
+	}
+}
+
+func TestSummaryUnrosteredPlayers(t *testing.T) {
+	game := testGame1()
+	AddGoal(&game, 3, "10:00", HOME, 41, 17, 0, "Even")
+
+	summary := summarise(game)
+
+	last := summary.Events[len(summary.Events)-1]
+	if len(last.Unrostered) != 1 || last.Unrostered[0] != 17 {
+		t.Errorf("Unexpected unrostered players: %v", last.Unrostered)
+	}
+}
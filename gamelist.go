@@ -1,10 +1,16 @@
 package main
 
+import "strings"
+
 type GameList struct {
-	ID         string
-	Name       string
-	Games      []string
-	LockedWith string
+	ID           string
+	Name         string
+	Games        []string
+	LockedWith   string
+	PointsSystem string
+	TieBreakers  []string
+	Discipline   *DisciplineRules // The league's suspension rules, if not the defaults
+	Theme        string           // Default theme for the list's pages
 }
 
 func NewGameList(name string) GameList {
@@ -29,3 +35,34 @@ func (list GameList) LinkCode() string {
 func (list *GameList) SetLockedWith(key string) {
 	list.LockedWith = key
 }
+
+// Calculates the league table for the games in the list, using the list's points rules.
+func (list GameList) Standings(games []Game) []Standing {
+	return Standings(games, GetPointsRules(list.PointsSystem), list.TieBreakers)
+}
+
+// Returns the tie-breaker applied at the given position, or "" if there are fewer.
+func (list GameList) TieBreaker(position int) string {
+	tieBreakers := ValidTieBreakers(list.TieBreakers)
+	if position < 0 || position >= len(tieBreakers) {
+		return ""
+	}
+	return tieBreakers[position]
+}
+
+// Describes the points system and tie-breakers used for the list's standings.
+func StandingsRulesSummary(list GameList) string {
+	var names []string
+	for _, tieBreaker := range ValidTieBreakers(list.TieBreakers) {
+		names = append(names, strings.ToLower(TieBreakers[tieBreaker]))
+	}
+	return GetPointsRules(list.PointsSystem).Name + " points, then " + strings.Join(names, ", ")
+}
+
+// Returns the suspension rules for the list.
+func (list GameList) DisciplineRules() DisciplineRules {
+	if list.Discipline == nil {
+		return DefaultDisciplineRules
+	}
+	return *list.Discipline
+}
@@ -2,14 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html"
 	"io"
+	"net/url"
 	"strconv"
 	"time"
 
-	"os"
-
 	"html/template"
 	"net/http"
 	"strings"
@@ -31,47 +31,68 @@ type pageData struct {
 	EventHA     string
 	PageHeading string
 	Stylesheet  string
+	Theme       string // Default theme for the page, if the visitor hasn't chosen one
+	Lang        string
 	ItemType    string
 	ItemCode    string
 	Csrf        interface{}
 	History     []HistoryItem
+	Deleted     []DeletedItem
 	Detail      interface{}
 }
 
-type Template struct {
-	//templates *template.Template
-}
-
-func addRoutes(e *echo.Echo) {
-	e.Renderer = &Template{}
+func addRoutes(e *echo.Echo, renderer *Template) {
+	e.Renderer = renderer
 
+	e.Use(requestMetrics)
 	e.Use(middleware.Recover())
-	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{TokenLookup: "form:_csrf"}))
+	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{TokenLookup: "header:X-CSRF-Token,form:_csrf"}))
 
-	e.Static("/static", "template/static")
-	e.File("/robots.txt", "template/static/robots.txt")
+	addAssetHandlers(e, staticFiles(renderer.reload))
+	AddOfflineHandlers(e, staticFiles(renderer.reload))
 
+	AddHealthHandlers(e)
 	AddBotHandlers(e)
 	AddSsoHandlers(e)
+	AddTeamHandlers(e)
 
 	e.GET("/", homePage)
 	e.GET("/games", codeRedirect)
 	e.GET("/lists", codeRedirect)
 	e.GET("/game/:id", gamePage)
+	e.GET("/game/:id/history", gameHistoryPage)
 	e.GET("/sharegame", shareLink)
 	e.GET("/share", shareLink)
 	e.GET("/qrcode", qrCodeGenerator)
 	e.GET("/newEvent", newEventPage)
 	e.POST("/addEvent", addEventPost)
+	e.GET("/faceoff", faceoffPage)
+	e.POST("/addFaceoff", addFaceoffPost)
+	e.POST("/clock", clockPost)
+	e.POST("/period", periodPost)
+	e.GET("/finalise", finaliseGamePage)
+	e.POST("/finalise", finaliseGamePost)
+	e.POST("/reopen", reopenGamePost)
+	e.GET("/officials", officialsPage)
+	e.POST("/officials", officialsPost)
+	e.POST("/signGame", signGamePost)
 	e.GET("/newGame", newGamePage)
 	e.POST("/addGame", addGamePost)
 	e.GET("/deleteEvent", deleteEventPage)
 	e.POST("/deleteGameEvent", deleteEventPost)
 	e.GET("/error", errorPage)
 	e.GET("/setstyle", styleSet)
+	e.GET("/setlang", languageSet)
 	e.GET("/addPlayer", addPlayerPage)
 	e.POST("/addPlayer", addPlayerPost)
+	e.POST("/removePlayer", removePlayerPost)
+	e.GET("/export", exportItem)
 	e.GET("/list/:id", gameListPage)
+	e.GET("/list/:id/stats", listStatsPage)
+	e.GET("/list/:id/discipline", listDisciplinePage)
+	e.POST("/disciplineRules", disciplineRulesPost)
+	e.GET("/api/list/:id/standings", listStandingsApi)
+	e.POST("/listSettings", listSettingsPost)
 	e.GET("/newList", newListPage)
 	e.POST("/addList", addListPost)
 	e.POST("/addListGame", addListGamePost)
@@ -80,22 +101,13 @@ func addRoutes(e *echo.Echo) {
 	e.GET("/delete", deleteItemPage)
 	e.POST("/delete", deleteItemPost)
 	e.GET("/deleted", deletedItemPage)
+	e.POST("/restore", restoreItemPost)
 	e.GET("/help", helpPage)
 	e.GET("/cookies", cookiePage)
 	e.GET("/privacy", privacyPage)
 }
 
-func (t *Template) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
-	return showTemplatePage(name, data, w, c)
-}
-
-func showTemplatePage(templateName string, data any, w io.Writer, c echo.Context) error {
-	t, err := template.ParseFiles("template/base.html", "template/"+templateName+".html")
-	if err != nil {
-		logs.error("Error parsing template: %+v", err)
-		os.Exit(-2)
-	}
-
+func showTemplatePage(t *template.Template, data any, w io.Writer, c echo.Context) error {
 	if data == nil {
 		data = pageData{}
 	}
@@ -103,57 +115,32 @@ func showTemplatePage(templateName string, data any, w io.Writer, c echo.Context
 	data1, ok := data.(pageData)
 	if ok {
 		data1.Csrf = c.Get(middleware.DefaultCSRFConfig.ContextKey)
+		data1.Lang = negotiateLanguage(c)
 
 		if data1.PageHeading == "" {
 			data1.PageHeading = "Ice Hockey Scoresheet"
 		}
 
-		stylecookie, err := getStyleCookie(c)
-		if err == nil && stylecookie.Value != "" {
-			data1.Stylesheet = stylecookie.Value
-		} else {
-			data1.Stylesheet = "scoresheet-simple"
-			setStyleCookie(data1.Stylesheet, c)
-		}
+		data1.Stylesheet = chooseTheme(c, data1.Theme).Stylesheet()
 
 		data = data1
 	}
 
 	setSecurityHeaders(c)
 
-	if err := t.ExecuteTemplate(w, "base", data); err != nil {
-		//msg := http.StatusText(http.StatusInternalServerError)
+	err := t.ExecuteTemplate(w, "base", data)
+	if err != nil {
 		logs.error("template.Execute: %v", err)
-		//http.Error(w, msg, http.StatusInternalServerError)
 	}
 
 	return err
 }
 
 func setSecurityHeaders(c echo.Context) {
-	c.Response().Header().Set("Content-Security-Policy", "default-src 'self'; "+
-		"img-src 'self' cdn.jsdelivr.net/; "+
-		"script-src 'self' cdn.jsdelivr.net/; "+
-		"style-src 'self' cdn.jsdelivr.net/; ",
-	)
+	c.Response().Header().Set("Content-Security-Policy", "default-src 'self'")
 	c.Response().Header().Set("Cross-Origin-Opener-Policy", "same-origin")
 }
 
-func getStyleCookie(c echo.Context) (*http.Cookie, error) {
-	return c.Cookie("scoresheetstyle")
-}
-
-func setStyleCookie(stylesheetName string, c echo.Context) {
-	stylecookie := new(http.Cookie)
-	stylecookie.Name = "scoresheetstyle"
-	stylecookie.Path = "/"
-	stylecookie.Value = stylesheetName
-	stylecookie.SameSite = http.SameSiteStrictMode
-	stylecookie.Secure = true
-	stylecookie.Expires = time.Now().Add(2 * 365 * time.Hour)
-	c.SetCookie(stylecookie)
-}
-
 func homePage(c echo.Context) error {
 	logs.info("Received request: %s", c.Path())
 
@@ -183,6 +170,11 @@ func codeRedirect(c echo.Context) error {
 		return c.Redirect(http.StatusSeeOther, "/list/"+listId)
 	}
 
+	teamId := strings.ToUpper(c.QueryParam("team_id"))
+	if teamId != "" {
+		return c.Redirect(http.StatusSeeOther, "/team/"+teamId)
+	}
+
 	return c.Redirect(http.StatusSeeOther, "/")
 }
 
@@ -190,9 +182,67 @@ func errorMessage(errorCode string) string {
 	if errorCode == "8001" {
 		return "Unable to unlock game for editing"
 	}
+	if errorCode == "8002" {
+		return "Player number must be a number"
+	}
+	if errorCode == "8003" {
+		return "Unable to edit locked team"
+	}
+	if errorCode == "8004" {
+		return "Unable to edit locked list"
+	}
+	if errorCode == "8005" {
+		return "Game is final, reopen it to make changes"
+	}
+	if errorCode == "8006" {
+		return "Unable to sign the scoresheet, the game must be final and a name is required"
+	}
+	if errorCode == "8007" {
+		return "Suspension rules must be whole numbers of zero or more"
+	}
+	if errorCode == "8008" {
+		return "Penalty is not in the penalty catalogue for this competition"
+	}
+	if errorCode == "8009" {
+		return "Penalty minutes do not match the type of penalty"
+	}
+	if errorCode == "8010" {
+		return "Period must be from 1 to 4, where 4 is overtime"
+	}
+	if errorCode == "8011" {
+		return "Unable to sign the scoresheet, the game must be locked and its unlock key given"
+	}
+	if errorCode == "8012" {
+		return "Periods can only be started when the game is scheduled or in an intermission, and ended when it is in progress"
+	}
 	return ""
 }
 
+// An ErrorCode is returned when a change to a game is not allowed, so that the game page can
+// show the error message for the code.
+type ErrorCode string
+
+func (code ErrorCode) Error() string {
+	return errorMessage(string(code))
+}
+
+// Responds to a change to a game that was not made, either with the game page showing the
+// error, or with the HTTP error returned by the change.
+func gameUpdateFailed(c echo.Context, gameId string, doing string, err error) error {
+	var code ErrorCode
+	var httpError *echo.HTTPError
+	switch {
+	case errors.Is(err, errGameNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Game not found when %s: %s", doing, gameId))
+	case errors.As(err, &code):
+		return c.Redirect(http.StatusSeeOther, "/game/"+gameId+"?e="+string(code))
+	case errors.As(err, &httpError):
+		return httpError
+	}
+	logs.error1(gctx(c), "Unable to update game %s when %s: %v", gameId, doing, err)
+	return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Unable to update game: %s", gameId))
+}
+
 type GameRequestKeyType string
 
 const GameRequestKey = GameRequestKeyType("game_request")
@@ -253,11 +303,13 @@ func gamePage(c echo.Context) error {
 	data.PageHeading = data.Game.Title
 
 	if data.Game.ID != gameId {
-		return showErrorPage(fmt.Sprintf("Game not found: %s", gameId), c)
+		return showMissingItem("game", gameId, c)
 	}
 
 	SortEvents(&(data.Game))
 	data.Summary = summarise(data.Game)
+	data.Deleted = dataStore.deletedEvents(ctx, gameId)
+	data.Detail = clockView(data.Game.Clock, time.Now())
 
 	errorCode := c.QueryParam("e")
 	if errorCode != "" {
@@ -335,6 +387,12 @@ func newEventPage(c echo.Context) error {
 
 	data := pageData{
 		Game: game,
+		Detail: NewEventData{
+			EventDefaults: eventDefaults(game, time.Now()),
+			EventID:       randomEventId(),
+			Catalogue:     CatalogueFor(game.Competition),
+			PenaltyTypes:  PenaltyTypes,
+		},
 	}
 
 	if eventType[0:1] == "A" {
@@ -343,17 +401,340 @@ func newEventPage(c echo.Context) error {
 		data.EventHA = "Home"
 	}
 
-	if eventType[1:2] == "P" {
-		data.EventType = "Penalty"
-	} else {
-		data.EventType = "Goal"
-	}
+	data.EventType = EventTypeFromCode(eventType[1:2])
 
 	data.PageHeading = data.EventHA + " " + data.EventType + ", " + game.Title
 
 	return c.Render(http.StatusOK, "newevent", data)
 }
 
+type NewEventData struct {
+	EventDefaults
+	EventID      string // Sent back with the form so that submitting it twice adds one event
+	Catalogue    PenaltyCatalogue
+	PenaltyTypes []string
+}
+
+type EventDefaults struct {
+	Period  int
+	Minutes string
+	Seconds string
+}
+
+// Returns the default period and clock time for a new event, taken from the game clock
+// if it is in use.
+func eventDefaults(game Game, now time.Time) EventDefaults {
+	if !game.Clock.Enabled {
+		return EventDefaults{Period: game.Period}
+	}
+	remaining := game.Clock.RemainingAt(now)
+	return EventDefaults{
+		Period:  game.Clock.Period,
+		Minutes: strconv.Itoa(remaining / 60),
+		Seconds: strconv.Itoa(remaining % 60),
+	}
+}
+
+// Updates the game clock, e.g. starting or stopping it.
+func clockPost(c echo.Context) error {
+	gameId := c.FormValue("game_id")
+
+	ctx := gctx(c)
+
+	now := time.Now()
+	action := strings.ToLower(c.FormValue("action"))
+	before := ""
+
+	game, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		before = clockView(game.Clock, now).Describe()
+
+		switch action {
+		case "enable":
+			game.Clock = NewGameClock(game.Period)
+		case "disable":
+			game.Clock = GameClock{}
+		case "start":
+			game.Clock.Start(now)
+		case "stop":
+			game.Clock.Stop(now)
+		case "next":
+			game.Clock.NextPeriod()
+		case "set":
+			period, _ := strconv.Atoi(c.FormValue("period"))
+			if !ValidPeriod(period) {
+				return ErrorCode("8010")
+			}
+			minutes, _ := strconv.Atoi(c.FormValue("minutes"))
+			seconds, _ := strconv.Atoi(c.FormValue("seconds"))
+			game.Clock.Set(period, minutes*60+seconds)
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown clock action: %s", action))
+		}
+		return nil
+	})
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "updating clock", err)
+	}
+
+	logs.debug1(ctx, "Clock %s for game %s: %+v", action, gameId, game.Clock)
+	logGameChange(ctx, gameId, "Clock "+action, before, clockView(game.Clock, now).Describe())
+
+	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+}
+
+// Records the start or end of a period, keeping the game clock in step if it is in use.
+func periodPost(c echo.Context) error {
+	gameId := c.FormValue("game_id")
+
+	ctx := gctx(c)
+
+	now := time.Now()
+	status := ""
+
+	game, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		if game.IsFinal() {
+			return ErrorCode("8005")
+		}
+		status = game.CurrentStatus()
+
+		switch strings.ToLower(c.FormValue("action")) {
+		case "start":
+			if status != STATUS_SCHEDULED && status != STATUS_INTERMISSION {
+				return ErrorCode("8012")
+			}
+			period := LastPeriodEnded(*game) + 1
+			if !ValidPeriod(period) {
+				return ErrorCode("8010")
+			}
+			AddPeriodEvent(game, PERIOD_START, period)
+			if game.Clock.Enabled {
+				game.Clock.Set(period, PERIOD_SECONDS)
+				game.Clock.Start(now)
+			}
+		case "end":
+			if status != STATUS_IN_PROGRESS {
+				return ErrorCode("8012")
+			}
+			AddPeriodEvent(game, PERIOD_END, game.Period)
+			if game.Clock.Enabled {
+				game.Clock.Stop(now)
+				game.Clock.Set(game.Period, 0)
+			}
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, "Unknown period action")
+		}
+		return nil
+	})
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "updating period", err)
+	}
+
+	logGameChange(ctx, gameId, "Add event", status, DescribeEvent(game.Events[len(game.Events)-1]))
+
+	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+}
+
+type FinaliseData struct {
+	Problems []string
+}
+
+func finaliseGamePage(c echo.Context) error {
+	gameId := c.QueryParam("game")
+
+	ctx := gctx(c)
+
+	game := dataStore.getGame(ctx, gameId)
+
+	if game.ID != gameId {
+		return showErrorPage(fmt.Sprintf("Game not found: %s", gameId), c)
+	}
+
+	data := pageData{
+		Game:        game,
+		PageHeading: "Finalise " + game.Title,
+		Detail:      FinaliseData{Problems: ValidateForFinal(game)},
+	}
+
+	return c.Render(http.StatusOK, "finalise", data)
+}
+
+func finaliseGamePost(c echo.Context) error {
+	gameId := c.FormValue("game_id")
+
+	ctx := gctx(c)
+
+	status := ""
+	var problems []string
+
+	game, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		status = game.CurrentStatus()
+
+		problems = Finalise(game, c.FormValue("lock_key"))
+		if len(problems) > 0 {
+			return errUnchanged
+		}
+		return nil
+	})
+	if err == errUnchanged {
+		logs.info1(ctx, "Game %s cannot be finalised: %v", gameId, problems)
+		return c.Redirect(http.StatusSeeOther, "/finalise?game="+gameId)
+	}
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "finalising", err)
+	}
+
+	logGameChange(ctx, gameId, "Finalise game", status, game.CurrentStatus())
+
+	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+}
+
+func reopenGamePost(c echo.Context) error {
+	gameId := c.FormValue("game_id")
+
+	ctx := gctx(c)
+
+	status := ""
+
+	game, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		status = game.CurrentStatus()
+
+		Reopen(game)
+		if game.CurrentStatus() == status {
+			return errUnchanged
+		}
+		return nil
+	})
+	if err == errUnchanged {
+		return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+	}
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "reopening", err)
+	}
+
+	logGameChange(ctx, gameId, "Reopen game", status, game.CurrentStatus())
+
+	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+}
+
+func officialsPage(c echo.Context) error {
+	gameId := c.QueryParam("game")
+
+	ctx := gctx(c)
+
+	game := dataStore.getGame(ctx, gameId)
+
+	if game.ID != gameId {
+		return showErrorPage(fmt.Sprintf("Game not found: %s", gameId), c)
+	}
+
+	data := pageData{
+		Game:        game,
+		PageHeading: "Officials, " + game.Title,
+	}
+
+	return c.Render(http.StatusOK, "officials", data)
+}
+
+func officialsPost(c echo.Context) error {
+	gameId := c.FormValue("game_id")
+
+	ctx := gctx(c)
+
+	var officials Officials
+	err := c.Bind(&officials)
+	if err != nil {
+		logs.debug1(ctx, "Bind errors: %v", err)
+	}
+
+	before := ""
+
+	game, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		before = DescribeOfficials(*game)
+
+		game.Officials = officials
+		game.HomeStaff = TeamStaff{Coach: c.FormValue("home_coach"), Manager: c.FormValue("home_manager")}
+		game.AwayStaff = TeamStaff{Coach: c.FormValue("away_coach"), Manager: c.FormValue("away_manager")}
+		return nil
+	})
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "updating officials", err)
+	}
+
+	logGameChange(ctx, gameId, "Update officials", before, DescribeOfficials(game))
+
+	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+}
+
+// Records a coach or official confirming the final scoresheet.
+func signGamePost(c echo.Context) error {
+	gameId := c.FormValue("game_id")
+
+	ctx := gctx(c)
+
+	game, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		// Signing off makes the game permanent. There are no logins, so a typed name alone would
+		// let anyone with the game ID sign, and the game's unlock key is asked for as well.
+		if game.LockedWith == "" || strings.TrimSpace(c.FormValue("unlock_key")) != game.LockedWith {
+			logs.info1(ctx, "Unable to sign game %s without its unlock key", gameId)
+			return ErrorCode("8011")
+		}
+
+		if err := SignOff(game, c.FormValue("role"), c.FormValue("signed_name"), time.Now()); err != nil {
+			logs.info1(ctx, "Unable to sign game %s: %v", gameId, err)
+			return ErrorCode("8006")
+		}
+		return nil
+	})
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "signing", err)
+	}
+
+	signature := game.Signatures[len(game.Signatures)-1]
+	logGameChange(ctx, gameId, "Sign scoresheet", "", signature.Role+": "+signature.Name)
+
+	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+}
+
+// Records a change to a game in its change log.
+func logGameChange(ctx context.Context, gameId string, action string, before string, after string) {
+	dataStore.recordChange(ctx, gameId, NewGameChange(ctx, action, before, after))
+}
+
+func gameHistoryPage(c echo.Context) error {
+	gameId := c.Param("id")
+
+	ctx := gctx(c)
+
+	game := dataStore.getGame(ctx, gameId)
+
+	if game.ID != gameId {
+		return showErrorPage(fmt.Sprintf("Game not found: %s", gameId), c)
+	}
+
+	data := pageData{
+		Game:        game,
+		PageHeading: game.Title,
+		Detail:      dataStore.getChangeLog(ctx, gameId).Latest(),
+	}
+
+	return c.Render(http.StatusOK, "gamehistory", data)
+}
+
 func addEventPost(c echo.Context) error {
 	gameId := c.FormValue("game_id")
 
@@ -363,21 +744,170 @@ func addEventPost(c echo.Context) error {
 
 	var event Event
 
+	if game.IsLocked() {
+		return c.Redirect(http.StatusSeeOther, "/game/"+gameId+"?e=8001")
+	}
+	if game.IsFinal() {
+		return c.Redirect(http.StatusSeeOther, "/game/"+gameId+"?e=8005")
+	}
+
 	err := c.Bind(&event)
 	logs.debug("Bind errors: %v", err)
 
-	event.ClockTime = EventTime(c.FormValue("minutes") + ":" + c.FormValue("seconds"))
+	form, _ := c.FormParams()
+	if code := prepareEvent(game, &event, form); code != "" {
+		return c.Redirect(http.StatusSeeOther, "/game/"+gameId+"?e="+code)
+	}
+
+	_, added, err := dataStore.addEvent(ctx, gameId, event)
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "adding event", err)
+	}
+	if added {
+		logGameChange(ctx, gameId, "Add event", "", DescribeEvent(event))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+}
+
+// Fills in the parts of a new event that are not bound directly from the form, and checks
+// it is valid. Returns an error code if it is not.
+func prepareEvent(game Game, event *Event, form url.Values) string {
+	if !ValidPeriod(event.Period) {
+		return "8010"
+	}
+
+	event.ID = clientEventId(form.Get("event_id"))
+	event.ClockTime = EventTime(form.Get("minutes") + ":" + form.Get("seconds"))
 	event.GameTime = ClockToGameTime(event.Period, event.ClockTime)
 
-	AddEvent(&game, event)
+	if event.EventType == PENALTY {
+		if extra := form.Get("additional_penalty"); extra != "" {
+			event.Components = []PenaltyComponent{{Type: extra}}
+		}
+		switch CatalogueFor(game.Competition).Validate(event) {
+		case PENALTY_UNKNOWN:
+			return "8008"
+		case PENALTY_BAD_MINUTES:
+			return "8009"
+		}
+	} else {
+		event.PenaltyType = ""
+		event.Coincidental = false
+	}
 
-	dataStore.putGame(ctx, gameId, game)
+	if event.EventType != PENALTY_SHOT || LinkedPenalty(game, *event) == nil {
+		event.PenaltyEventID = ""
+	}
+	if event.EventType != PENALTY_SHOT || !validOutcome(event.Outcome) {
+		event.Outcome = ""
+	}
+	return ""
+}
 
-	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+type FaceoffPageData struct {
+	EventDefaults
+	HomeCentre int
+	AwayCentre int
+	Count      int
+}
+
+// Shows the rapid entry form for faceoffs, which returns to itself after each one so that
+// faceoffs can be recorded one after another.
+func faceoffPage(c echo.Context) error {
+	gameId := c.QueryParam("game")
+
+	ctx := gctx(c)
+
+	game := dataStore.getGame(ctx, gameId)
+
+	if game.ID != gameId {
+		return showErrorPage(fmt.Sprintf("Game not found when adding faceoff: %s", gameId), c)
+	}
+
+	detail := FaceoffPageData{
+		EventDefaults: eventDefaults(game, time.Now()),
+	}
+	detail.HomeCentre, detail.AwayCentre = LastCentres(game)
+	for _, event := range game.Events {
+		if event.EventType == FACEOFF {
+			detail.Count++
+		}
+	}
+
+	data := pageData{
+		Game:        game,
+		Detail:      detail,
+		PageHeading: "Faceoffs, " + game.Title,
+	}
+
+	errorCode := c.QueryParam("e")
+	if errorCode != "" {
+		data.Error = errorMessage(errorCode)
+	}
+
+	return c.Render(http.StatusOK, "faceoff", data)
+}
+
+func addFaceoffPost(c echo.Context) error {
+	gameId := c.FormValue("game_id")
+
+	ctx := gctx(c)
+
+	winner := c.FormValue("home_away")
+	if winner != HOME && winner != AWAY {
+		return echo.NewHTTPError(http.StatusBadRequest, "Faceoff winner must be Home or Away")
+	}
+	zone := faceoffZone(c.FormValue("end"), winner)
+	if zone == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Faceoff end must be Home, Away or Neutral")
+	}
+
+	period, _ := strconv.Atoi(c.FormValue("period"))
+	centres := make(map[string]int)
+	centres[HOME], _ = strconv.Atoi(c.FormValue("home_centre"))
+	centres[AWAY], _ = strconv.Atoi(c.FormValue("away_centre"))
+
+	event := Event{
+		ID:        randomEventId(),
+		Period:    period,
+		ClockTime: EventTime(c.FormValue("minutes") + ":" + c.FormValue("seconds")),
+		EventType: FACEOFF,
+		HomeAway:  winner,
+		Zone:      zone,
+		Player:    centres[winner],
+		Opponent:  centres[opposingTeam(winner)],
+	}
+	event.GameTime = ClockToGameTime(event.Period, event.ClockTime)
+
+	_, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		if game.IsFinal() {
+			return ErrorCode("8005")
+		}
+		if !ValidPeriod(period) {
+			return ErrorCode("8010")
+		}
+
+		AddEvent(game, event)
+		return nil
+	})
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "adding faceoff", err)
+	}
+
+	logGameChange(ctx, gameId, "Add event", "", DescribeEvent(event))
+
+	return c.Redirect(http.StatusSeeOther, "/faceoff?game="+gameId)
 }
 
 func newGamePage(c echo.Context) error {
-	data := pageData{}
+	data := pageData{
+		History: HistoryOfType(getHistory(c), "team"),
+		Detail:  CatalogueNames(),
+	}
 	return c.Render(http.StatusOK, "newgame", data)
 }
 
@@ -388,6 +918,11 @@ func addGamePost(c echo.Context) error {
 
 	c.Bind(&game)
 	game.Created = time.Now()
+	game.Status = STATUS_SCHEDULED
+
+	ctx := gctx(c)
+	copyRegistryRoster(ctx, &game, HOME, strings.ToUpper(strings.TrimSpace(game.HomeTeamID)))
+	copyRegistryRoster(ctx, &game, AWAY, strings.ToUpper(strings.TrimSpace(game.AwayTeamID)))
 
 	gameDate, err := time.Parse("2006-01-02", game.GameDate)
 	if err == nil {
@@ -397,11 +932,32 @@ func addGamePost(c echo.Context) error {
 		game.Title = game.AwayTeam + " @ " + game.HomeTeam + " on " + game.GameDate
 	}
 
-	gameId := dataStore.addGame(context.Background(), game)
+	gameId := dataStore.addGame(ctx, game)
+	logGameChange(ctx, gameId, "Create game", "", game.Title)
 
 	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
 }
 
+// Copies the roster of a registered team into the game, if the team can be found.
+func copyRegistryRoster(ctx context.Context, game *Game, homeAway string, teamId string) {
+	if homeAway == HOME {
+		game.HomeTeamID = ""
+	} else {
+		game.AwayTeamID = ""
+	}
+	if teamId == "" {
+		return
+	}
+
+	team := dataStore.getTeam(ctx, teamId)
+	if team.ID != teamId {
+		logs.info1(ctx, "Team not found when creating game: %s", teamId)
+		return
+	}
+
+	CopyRoster(game, homeAway, team)
+}
+
 func deleteEventPage(c echo.Context) error {
 	gameId := c.QueryParam("game")
 
@@ -431,43 +987,39 @@ func deleteEventPost(c echo.Context) error {
 	requestedEvent := c.FormValue("event_summary")
 	logs.debug("Received delete event request for %s, %s", gameId, requestedEvent)
 
-	game := dataStore.getGame(ctx, gameId)
+	var deleted Event
 
-	if game.ID != gameId {
-		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Game not found when deleting event: %s", gameId))
-	}
-	if game.LockedWith != "" {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Attempting to delete event from locked game: %s", gameId))
-	}
-
-	for n, event := range game.Events {
-		event_summary := fmt.Sprintf("%s %s %s", event.GameTime, event.HomeAway, event.EventType)
-		if event_summary == requestedEvent {
-			logs.debug("Found event to delete")
-			event.GameTime = "99:99"
-			event.Period = 0
-			event.ClockTime = ""
-			event.EventType = "DELETED"
-			event.HomeAway = ""
-			event.Category = ""
-			event.Player = 0
-			event.Assist1 = 0
-			event.Assist2 = 0
-			event.Minutes = 0
+	_, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Attempting to delete event from locked game: %s", gameId))
+		}
+		if game.IsFinal() {
+			return ErrorCode("8005")
 		}
-		game.Events[n] = event
-	}
 
-	SortEvents(&game)
+		eventId := c.FormValue("event_id")
+		if eventId == "" {
+			for _, event := range game.Events {
+				event_summary := fmt.Sprintf("%s %s %s", event.GameTime, event.HomeAway, event.EventType)
+				if event_summary == requestedEvent {
+					eventId = event.ID
+				}
+			}
+		}
 
-	eventCount := len(game.Events)
-	if game.Events[eventCount-1].EventType == "DELETED" {
-		game.Events = game.Events[:eventCount-1]
-	} else {
-		logs.error("Last event not marked for deletion")
+		event, found := RemoveEvent(game, eventId)
+		if !found {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Event not found when deleting event: %s", requestedEvent))
+		}
+		deleted = event
+		return nil
+	})
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "deleting event", err)
 	}
 
-	dataStore.putGame(ctx, gameId, game)
+	dataStore.binEvent(ctx, gameId, deleted)
+	logGameChange(ctx, gameId, "Delete event", DescribeEvent(deleted), "")
 
 	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
 }
@@ -486,6 +1038,10 @@ func lockItemPage(c echo.Context) error {
 		errorText = "Incorrect unlock key"
 	} else if errorCode == "1002" {
 		errorText = "Unlock key must not be empty"
+	} else if errorCode == "1003" {
+		errorText = "Already locked, unlock it first to change the key"
+	} else if errorCode == "1004" {
+		errorText = "Signed off games cannot be locked or unlocked"
 	}
 	lockdata := lockData{
 		Type:   c.QueryParam("type"),
@@ -509,6 +1065,9 @@ func lockItemPost(c echo.Context) error {
 
 	ctx := gctx(c)
 
+	if action != "lock" && action != "unlock" {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown lock action: %s", action))
+	}
 	if action == "lock" && unlockKey == "" {
 		return c.Redirect(http.StatusSeeOther, "/lock?error=1002&action=Lock&type="+itemType+"&code="+itemCode)
 	}
@@ -528,17 +1087,47 @@ func lockItemPost(c echo.Context) error {
 		}
 		dataStore.putList(ctx, itemCode, list)
 	} else if itemType == "game" {
-		game := dataStore.getGame(ctx, itemCode)
+		lockError := ""
+		_, err := dataStore.updateGame(ctx, itemCode, func(game *Game) error {
+			// The key is needed to sign, so it can't be replaced without the old one
+			if game.SignedOff() {
+				lockError = "1004"
+				return errUnchanged
+			}
+			if action == "lock" {
+				if game.LockedWith != "" {
+					lockError = "1003"
+					return errUnchanged
+				}
+				game.LockedWith = unlockKey
+			} else if action == "unlock" {
+				if unlockKey != game.LockedWith {
+					lockError = "1001"
+					return errUnchanged
+				}
+				game.LockedWith = ""
+			}
+			return nil
+		})
+		if err == errUnchanged {
+			return c.Redirect(http.StatusSeeOther, "/lock?error="+lockError+"&action="+strings.ToUpper(action[:1])+action[1:]+"&type=Game&code="+itemCode)
+		}
+		if err != nil {
+			return gameUpdateFailed(c, itemCode, action+"ing", err)
+		}
+		logGameChange(ctx, itemCode, strings.ToUpper(action[:1])+action[1:]+" game", "", "")
+	} else if itemType == "team" {
+		team := dataStore.getTeam(ctx, itemCode)
 
 		if action == "lock" {
-			game.LockedWith = unlockKey
+			team.LockedWith = unlockKey
 		} else if action == "unlock" {
-			if unlockKey != game.LockedWith {
-				return c.Redirect(http.StatusSeeOther, "/lock?error=1001&action=Unlock&type=Game&code="+itemCode)
+			if unlockKey != team.LockedWith {
+				return c.Redirect(http.StatusSeeOther, "/lock?error=1001&action=Unlock&type=Team&code="+itemCode)
 			}
-			game.LockedWith = ""
+			team.LockedWith = ""
 		}
-		dataStore.putGame(ctx, itemCode, game)
+		dataStore.putTeam(ctx, itemCode, team)
 	}
 
 	itemUrl = "/" + itemType + "/" + itemCode
@@ -600,14 +1189,6 @@ func qrCodeGenerator(c echo.Context) error {
 	return q.Write(320, c.Response())
 }
 
-func styleSet(c echo.Context) error {
-	styleName := c.QueryParam("style")
-
-	setStyleCookie("scoresheet-"+styleName, c)
-
-	return c.Redirect(http.StatusSeeOther, "/")
-}
-
 func addPlayerPage(c echo.Context) error {
 	gameId := c.QueryParam("game")
 
@@ -622,22 +1203,84 @@ func addPlayerPost(c echo.Context) error {
 
 	ctx := gctx(c)
 
-	game := dataStore.getGame(ctx, gameId)
+	homeAway := c.FormValue("home_away")
+	playerNum, numErr := strconv.Atoi(c.FormValue("player_number"))
+
+	player := Player{
+		Number:         playerNum,
+		Name:           c.FormValue("player_name"),
+		Position:       c.FormValue("position"),
+		Role:           strings.ToUpper(c.FormValue("role")),
+		StartingGoalie: c.FormValue("starting_goalie") != "",
+		Scratched:      c.FormValue("scratched") != "",
+	}
 
-	if game.LockedWith != "" {
-		return c.Redirect(http.StatusSeeOther, "/game/"+gameId+"?e=8001")
+	before := ""
+
+	_, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		if game.IsFinal() {
+			return ErrorCode("8005")
+		}
+		if numErr != nil {
+			return ErrorCode("8002")
+		}
+
+		if roster := game.Roster(homeAway); roster != nil {
+			if existing := FindPlayer(*roster, playerNum); existing != nil {
+				before = DescribePlayer(homeAway, *existing)
+			}
+		}
+
+		AddPlayer(game, homeAway, player)
+		return nil
+	})
+	if err != nil {
+		return gameUpdateFailed(c, gameId, "adding player", err)
 	}
 
+	logGameChange(ctx, gameId, "Add player", before, DescribePlayer(homeAway, player))
+
+	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
+}
+
+func removePlayerPost(c echo.Context) error {
+	gameId := c.FormValue("game_id")
+
+	ctx := gctx(c)
+
 	homeAway := c.FormValue("home_away")
-	playerNum, err := strconv.Atoi(c.FormValue("player_number"))
+	playerNum, numErr := strconv.Atoi(c.FormValue("player_number"))
+
+	before := ""
+
+	_, err := dataStore.updateGame(ctx, gameId, func(game *Game) error {
+		if game.IsLocked() {
+			return ErrorCode("8001")
+		}
+		if game.IsFinal() {
+			return ErrorCode("8005")
+		}
+		if numErr != nil {
+			return ErrorCode("8002")
+		}
+
+		if roster := game.Roster(homeAway); roster != nil {
+			if existing := FindPlayer(*roster, playerNum); existing != nil {
+				before = DescribePlayer(homeAway, *existing)
+			}
+		}
+
+		RemovePlayer(game, homeAway, playerNum)
+		return nil
+	})
 	if err != nil {
-		return c.Redirect(http.StatusSeeOther, "/game/"+gameId+"?e=8002")
+		return gameUpdateFailed(c, gameId, "removing player", err)
 	}
-	playerName := c.FormValue("player_name")
-
-	AddPlayer(&game, homeAway, playerNum, playerName)
 
-	dataStore.putGame(ctx, gameId, game)
+	logGameChange(ctx, gameId, "Remove player", before, "")
 
 	return c.Redirect(http.StatusSeeOther, "/game/"+gameId)
 }
@@ -655,8 +1298,12 @@ func privacyPage(c echo.Context) error {
 }
 
 type ListPageData struct {
-	List  GameList
-	Games []Game
+	List          GameList
+	Games         []Game
+	Standings     []Standing
+	PointsSystems []PointsRules
+	TieBreakers   map[string]string
+	RulesSummary  string
 }
 
 func gameListPage(c echo.Context) error {
@@ -667,18 +1314,19 @@ func gameListPage(c echo.Context) error {
 
 	var listData ListPageData
 	listData.List = dataStore.getList(ctx, listId)
-
-	for _, gameId := range listData.List.Games {
-		game := dataStore.getGame(ctx, gameId)
-		listData.Games = append(listData.Games, game)
-	}
+	listData.Games = getListGames(ctx, listData.List)
+	listData.Standings = listData.List.Standings(listData.Games)
+	listData.PointsSystems = PointsSystems
+	listData.TieBreakers = TieBreakers
+	listData.RulesSummary = StandingsRulesSummary(listData.List)
 
 	var data pageData
 	data.Detail = listData
 	data.PageHeading = listData.List.Name
+	data.Theme = listData.List.Theme
 
 	if listData.List.ID != listId {
-		return showErrorPage(fmt.Sprintf("List not found: %s", listId), c)
+		return showMissingItem("list", listId, c)
 	}
 
 	errorCode := c.QueryParam("e")
@@ -691,6 +1339,183 @@ func gameListPage(c echo.Context) error {
 	return c.Render(http.StatusOK, "gamelist", data)
 }
 
+// Returns all the games in a list, skipping any that cannot be found.
+func getListGames(ctx context.Context, list GameList) []Game {
+	var games []Game
+	for _, gameId := range list.Games {
+		game := dataStore.getGame(ctx, gameId)
+		if game.ID == "" {
+			logs.info1(ctx, "Game %s in list %s not found", gameId, list.ID)
+			continue
+		}
+		games = append(games, game)
+	}
+	return games
+}
+
+type StatsPageData struct {
+	List  GameList
+	Teams []TeamStats
+	Sort  string
+}
+
+func listStatsPage(c echo.Context) error {
+	listId := c.Param("id")
+
+	ctx := gctx(c)
+	logs.info1(ctx, "GET for list statistics: %s", listId)
+
+	list := dataStore.getList(ctx, listId)
+	if list.ID != listId {
+		return showErrorPage(fmt.Sprintf("List not found: %s", listId), c)
+	}
+
+	stats := StatsPageData{
+		List:  list,
+		Teams: SeasonStatistics(getListGames(ctx, list)),
+		Sort:  validStatsColumn(c.QueryParam("sort")),
+	}
+	for _, team := range stats.Teams {
+		SortStats(team.Players, stats.Sort)
+	}
+
+	var data pageData
+	data.Detail = stats
+	data.PageHeading = list.Name
+	data.Theme = list.Theme
+
+	return c.Render(http.StatusOK, "liststats", data)
+}
+
+type DisciplinePageData struct {
+	List    GameList
+	Rules   DisciplineRules
+	Records []DisciplineRecord
+}
+
+// Shows the disciplinary report for the games in a list.
+func listDisciplinePage(c echo.Context) error {
+	listId := c.Param("id")
+
+	ctx := gctx(c)
+	logs.info1(ctx, "GET for list discipline: %s", listId)
+
+	list := dataStore.getList(ctx, listId)
+	if list.ID != listId {
+		return showErrorPage(fmt.Sprintf("List not found: %s", listId), c)
+	}
+
+	rules := list.DisciplineRules()
+
+	var data pageData
+	data.Detail = DisciplinePageData{
+		List:    list,
+		Rules:   rules,
+		Records: Discipline(getListGames(ctx, list), rules),
+	}
+	data.PageHeading = list.Name
+	data.Theme = list.Theme
+
+	errorCode := c.QueryParam("e")
+	if errorCode != "" {
+		data.Error = errorMessage(errorCode)
+	}
+
+	return c.Render(http.StatusOK, "listdiscipline", data)
+}
+
+func disciplineRulesPost(c echo.Context) error {
+	ctx := gctx(c)
+
+	listId := c.FormValue("list_id")
+
+	list := dataStore.getList(ctx, listId)
+	if list.ID != listId {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("List not found: %s", listId))
+	}
+	if list.IsLocked() {
+		return c.Redirect(http.StatusSeeOther, "/list/"+listId+"/discipline?e=8004")
+	}
+
+	var rules DisciplineRules
+	values := map[string]*int{
+		"game_misconduct_games": &rules.GameMisconductGames,
+		"match_penalty_games":   &rules.MatchPenaltyGames,
+		"pim_threshold":         &rules.PIMThreshold,
+		"pim_threshold_games":   &rules.PIMThresholdGames,
+	}
+	for field, value := range values {
+		number, err := strconv.Atoi(c.FormValue(field))
+		if err != nil || number < 0 {
+			return c.Redirect(http.StatusSeeOther, "/list/"+listId+"/discipline?e=8007")
+		}
+		*value = number
+	}
+	list.Discipline = &rules
+
+	dataStore.putList(ctx, listId, list)
+
+	return c.Redirect(http.StatusSeeOther, "/list/"+listId+"/discipline")
+}
+
+type StandingsResponse struct {
+	ListID       string
+	Name         string
+	PointsSystem string
+	TieBreakers  []string
+	Standings    []Standing
+}
+
+func listStandingsApi(c echo.Context) error {
+	listId := c.Param("id")
+
+	ctx := gctx(c)
+
+	list := dataStore.getList(ctx, listId)
+	if list.ID != listId {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("List not found: %s", listId))
+	}
+
+	response := StandingsResponse{
+		ListID:       list.ID,
+		Name:         list.Name,
+		PointsSystem: GetPointsRules(list.PointsSystem).Name,
+		TieBreakers:  ValidTieBreakers(list.TieBreakers),
+		Standings:    list.Standings(getListGames(ctx, list)),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func listSettingsPost(c echo.Context) error {
+	ctx := gctx(c)
+
+	listId := c.FormValue("list_id")
+
+	list := dataStore.getList(ctx, listId)
+	if list.ID != listId {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("List not found: %s", listId))
+	}
+	if list.IsLocked() {
+		return c.Redirect(http.StatusSeeOther, "/list/"+listId+"?e=8004")
+	}
+
+	list.PointsSystem = GetPointsRules(c.FormValue("points_system")).Name
+
+	var tieBreakers []string
+	for _, field := range []string{"tie_breaker_1", "tie_breaker_2", "tie_breaker_3"} {
+		if value := c.FormValue(field); value != "" {
+			tieBreakers = append(tieBreakers, value)
+		}
+	}
+	list.TieBreakers = ValidTieBreakers(tieBreakers)
+	list.Theme = ValidTheme(c.FormValue("theme"))
+
+	dataStore.putList(ctx, listId, list)
+
+	return c.Redirect(http.StatusSeeOther, "/list/"+listId)
+}
+
 func newListPage(c echo.Context) error {
 	return c.Render(http.StatusOK, "newlist", nil)
 }
@@ -737,18 +1562,122 @@ func deleteItemPost(c echo.Context) error {
 	if itemCode != confirmCode {
 		return echo.NewHTTPError(http.StatusBadRequest, "Code does not match")
 	}
+	if itemType == "game" && dataStore.getGame(gctx(c), confirmCode).SignedOff() {
+		return echo.NewHTTPError(http.StatusBadRequest, "Signed games cannot be deleted")
+	}
 
 	logs.info("Deleting %s %s at user's request", itemType, confirmCode)
 
-	dataStore.deleteItem(gctx(c), itemType, confirmCode)
+	if !dataStore.softDelete(gctx(c), itemType, confirmCode) {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Unable to delete %s %s", itemType, confirmCode))
+	}
+	if itemType == "game" {
+		logGameChange(gctx(c), confirmCode, "Delete game", "", "")
+	}
 
 	return c.Redirect(http.StatusSeeOther, "/deleted?type="+itemType+"&code="+confirmCode)
 }
 
+// Shows a deleted item, or the deleted items in the user's history, with the option to
+// restore them from the recycle bin.
 func deletedItemPage(c echo.Context) error {
 	var data pageData
-	data.ItemType = c.QueryParam("type")
+	data.ItemType = strings.ToLower(c.QueryParam("type"))
 	data.ItemCode = c.QueryParam("code")
 
+	ctx := gctx(c)
+	if data.ItemCode != "" {
+		item := dataStore.getDeleted(ctx, data.ItemType, data.ItemCode)
+		if item.ItemCode == data.ItemCode {
+			data.Deleted = []DeletedItem{item}
+		}
+	} else {
+		data.Deleted = DeletedFromHistory(ctx, getExistingHistory(c))
+	}
+	data.Detail = int(retentionPeriod().Hours() / 24)
+
 	return c.Render(http.StatusOK, "deleted", data)
 }
+
+// Redirects to the recycle bin if a missing item has been deleted, otherwise shows an error.
+func showMissingItem(itemType string, itemCode string, c echo.Context) error {
+	item := dataStore.getDeleted(gctx(c), itemType, itemCode)
+	if item.ItemCode == itemCode && itemCode != "" {
+		return c.Redirect(http.StatusSeeOther, "/deleted?type="+itemType+"&code="+itemCode)
+	}
+	return showErrorPage(fmt.Sprintf("%s not found: %s", strings.ToUpper(itemType[:1])+itemType[1:], itemCode), c)
+}
+
+// Restores an item from the recycle bin. Events can only be restored to unlocked games that
+// are not final.
+func restoreItemPost(c echo.Context) error {
+	itemType := strings.ToLower(c.FormValue("item_type"))
+	itemCode := c.FormValue("item_code")
+
+	ctx := gctx(c)
+
+	item, err := dataStore.restore(ctx, itemType, itemCode)
+	var code ErrorCode
+	if errors.As(err, &code) {
+		return c.Redirect(http.StatusSeeOther, "/game/"+item.GameID+"?e="+string(code))
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Unable to restore %s %s", itemType, itemCode))
+	}
+	logs.info1(ctx, "Restored %s %s at user's request", itemType, itemCode)
+
+	switch itemType {
+	case "event":
+		logGameChange(ctx, item.GameID, "Restore event", "", item.Summary)
+		return c.Redirect(http.StatusSeeOther, "/game/"+item.GameID)
+	case "game":
+		logGameChange(ctx, itemCode, "Restore game", "", item.Summary)
+	}
+	return c.Redirect(http.StatusSeeOther, "/"+itemType+"/"+itemCode)
+}
+
+// Downloads a CSV export of an item.
+func exportItem(c echo.Context) error {
+	itemType := strings.ToLower(c.QueryParam("type"))
+	itemCode := strings.ToUpper(c.QueryParam("code"))
+
+	ctx := gctx(c)
+
+	if itemType == "list" {
+		return exportListStats(c, itemCode)
+	}
+	if itemType != "game" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Unsupported export type")
+	}
+
+	game := dataStore.getGame(ctx, itemCode)
+	if game.ID != itemCode {
+		return showErrorPage(fmt.Sprintf("Game not found: %s", itemCode), c)
+	}
+	SortEvents(&game)
+
+	headers := c.Response().Header()
+	headers.Set("Content-Type", "text/csv")
+	headers.Set("Content-Disposition", "attachment; filename=\"game-"+game.ID+".csv\"")
+	c.Response().WriteHeader(http.StatusOK)
+
+	return WriteGameCsv(c.Response(), game)
+}
+
+func exportListStats(c echo.Context, listId string) error {
+	ctx := gctx(c)
+
+	list := dataStore.getList(ctx, listId)
+	if list.ID != listId {
+		return showErrorPage(fmt.Sprintf("List not found: %s", listId), c)
+	}
+
+	stats := SeasonStatistics(getListGames(ctx, list))
+
+	headers := c.Response().Header()
+	headers.Set("Content-Type", "text/csv")
+	headers.Set("Content-Disposition", "attachment; filename=\"stats-"+list.ID+".csv\"")
+	c.Response().WriteHeader(http.StatusOK)
+
+	return WriteStatsCsv(c.Response(), stats)
+}
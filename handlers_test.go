@@ -1,15 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/labstack/echo/v4"
 )
 
 func TestAddRoutes(t *testing.T) {
 	e := echo.New()
-	addRoutes(e)
+	addRoutes(e, testTemplates())
 
 	if len(e.Routes()) < 20 {
 		t.Errorf("Unexpected number of routes: %d", len(e.Routes()))
@@ -39,6 +44,7 @@ func TestGamePage(t *testing.T) {
 	wt.confirmSuccessResponse()
 
 	wt.confirmHtmlIncludes("h1", "Blues @ Reds")
+	wt.confirmHtmlIncludes("#home_scoring", "#41 Smith, J")
 	//wt.confirmHtmlIncludes("span", "P1&nbsp;14:25")
 }
 
@@ -89,6 +95,21 @@ func TestNewEventPage(t *testing.T) {
 	//wt.confirmHtmlIncludes("h1", "Home Goal, Blues @ Reds, 27 May 2024")
 }
 
+func TestAddEventPostRejectsBadPeriod(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1"})
+
+	wt := webTest(t)
+	wt.post("game_id=CODE1&period=7&minutes=5&seconds=0")
+
+	addEventPost(wt.ec)
+
+	wt.confirmRedirect("/game/CODE1?e=8010")
+	if len(dataStore.getGame(context.TODO(), "CODE1").Events) != 0 {
+		t.Error("Event with an invalid period should not be added")
+	}
+}
+
 func TestAddEventPost(t *testing.T) {
 	dataStore = GameStore{datastore: testDataStore()}
 	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1"})
@@ -179,4 +200,788 @@ func TestContentSecurityPolicy(t *testing.T) {
 	if wt.resp.Header().Get("Content-Security-Policy") == "" {
 		t.Error("No content security policy found in response")
 	}
+	if strings.Contains(wt.resp.Header().Get("Content-Security-Policy"), "cdn") {
+		t.Error("Content security policy should only allow the site itself")
+	}
+}
+
+func TestTeamPage(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setParam("id", TEST_TEAM_ID)
+	defer wt.showBodyOnFail()
+
+	teamPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("h1", "Reds")
+	wt.confirmHtmlIncludes("#team_roster", "Smith, J")
+}
+
+func TestAddGamePostWithTeam(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("away_team=Blues&home_team_id=" + TEST_TEAM_ID + "&game_date=2024-06-01")
+
+	addGamePost(wt.ec)
+
+	location := wt.resp.Header().Get("Location")
+	game := dataStore.getGame(context.TODO(), strings.TrimPrefix(location, "/game/"))
+	if game.HomeTeam != "Reds" {
+		t.Errorf("Home team not taken from registry: `%s`", game.HomeTeam)
+	}
+	if len(game.HomeRoster) != 4 {
+		t.Errorf("Unexpected home roster size: %d", len(game.HomeRoster))
+	}
+}
+
+func TestExportGame(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setQuery("type", "game")
+	wt.setQuery("code", TEST_ID_1)
+
+	exportItem(wt.ec)
+
+	wt.confirmSuccessResponse()
+	body := wt.resp.Body.String()
+	if !strings.Contains(body, "Home,41,\"Smith, J\",C,C,,Dressed") {
+		t.Errorf("Roster not found in export: %s", body)
+	}
+	if !strings.Contains(body, "1,18:30,01:30,Home,Goal,Even,41,89,93,") {
+		t.Errorf("Goal not found in export: %s", body)
+	}
+}
+
+func TestListStatsPage(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setParam("id", TEST_LIST_ID)
+	defer wt.showBodyOnFail()
+
+	listStatsPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("h4", "Reds")
+	wt.confirmHtmlIncludes(".player-stats", "Smith, J")
+}
+
+func TestListStandingsApi(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setParam("id", TEST_LIST_ID)
+	defer wt.showBodyOnFail()
+
+	listStandingsApi(wt.ec)
+
+	wt.confirmSuccessResponse()
+	body := wt.resp.Body.String()
+	if !strings.Contains(body, `"Team":"Greens"`) || !strings.Contains(body, `"PointsSystem":"2-1-0"`) {
+		t.Errorf("Unexpected standings response: %s", body)
+	}
+}
+
+func TestGameListPage(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setParam("id", TEST_LIST_ID)
+	defer wt.showBodyOnFail()
+
+	gameListPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("#standings", "Greens")
+}
+
+func TestGameListPageShowsSavedTieBreakers(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+	list := dataStore.getList(context.TODO(), TEST_LIST_ID)
+	list.TieBreakers = []string{"gf", "wins"}
+	dataStore.putList(context.TODO(), TEST_LIST_ID, list)
+
+	wt := webTest(t)
+	wt.setParam("id", TEST_LIST_ID)
+	defer wt.showBodyOnFail()
+
+	gameListPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	for selector, expected := range map[string]string{"#tie_breaker_1": "gf", "#tie_breaker_2": "wins", "#tie_breaker_3": ""} {
+		selected, _ := wt.document().Find(selector + " option[selected]").Attr("value")
+		if selected != expected {
+			wt.failed = true
+			t.Errorf("Expected %s to have %s selected, got %s", selector, expected, selected)
+		}
+	}
+}
+
+func TestClockPost(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&action=enable")
+
+	clockPost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_1)
+
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	if !game.Clock.Enabled || game.Clock.Period != game.Period {
+		t.Errorf("Clock not enabled: %+v", game.Clock)
+	}
+
+	page := webTest(t)
+	page.setParam("id", TEST_ID_1)
+	defer page.showBodyOnFail()
+	gamePage(page.ec)
+	page.confirmHtmlIncludes("#game_clock", "20:00")
+
+	game.Clock.Set(2, 605)
+	defaults := eventDefaults(game, time.Now())
+	if defaults.Period != 2 || defaults.Minutes != "10" || defaults.Seconds != "5" {
+		t.Errorf("Unexpected event defaults from clock: %+v", defaults)
+	}
+}
+
+func TestClockPostRejectsBadPeriod(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	for _, period := range []string{"0", "5", "x"} {
+		wt := webTest(t)
+		wt.post("game_id=" + TEST_ID_1 + "&action=set&period=" + period + "&minutes=10&seconds=0")
+
+		clockPost(wt.ec)
+
+		wt.confirmRedirect("/game/" + TEST_ID_1 + "?e=8010")
+	}
+	if game := dataStore.getGame(context.TODO(), TEST_ID_1); game.Clock.Remaining != 0 {
+		t.Errorf("Clock should not be set: %+v", game.Clock)
+	}
+}
+
+func TestFinaliseGamePost(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&lock_key=done")
+
+	finaliseGamePost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_1)
+
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	if !game.IsFinal() || game.LockedWith != "done" {
+		t.Errorf("Game not finalised and locked: %s, %s", game.Status, game.LockedWith)
+	}
+}
+
+func TestReopenUnknownGame(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+
+	wt := webTest(t)
+	wt.post("game_id=NOSUCHGAME")
+
+	err := reopenGamePost(wt.ec)
+
+	if httpError, ok := err.(*echo.HTTPError); !ok || httpError.Code != http.StatusNotFound {
+		t.Errorf("Expected not found, got %v", err)
+	}
+	if dataStore.datastore.Exists(context.TODO(), GAMES_COLLECTION, "NOSUCHGAME") {
+		t.Error("Reopening an unknown game should not create it")
+	}
+}
+
+func TestFinalGameChangesRejected(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	final := Game{ID: "CODE1", Status: STATUS_FINAL, Events: []Event{{ID: "E1", Period: 1, EventType: "Goal", HomeAway: HOME}}}
+	dataStore.putGame(context.TODO(), "CODE1", final)
+
+	for name, handler := range map[string]echo.HandlerFunc{
+		"delete event":  deleteEventPost,
+		"add player":    addPlayerPost,
+		"remove player": removePlayerPost,
+	} {
+		wt := webTest(t)
+		wt.post("game_id=CODE1&event_id=E1&home_away=Home&player_number=9")
+
+		handler(wt.ec)
+
+		wt.confirmRedirect("/game/CODE1?e=8005")
+		game := dataStore.getGame(context.TODO(), "CODE1")
+		if len(game.Events) != 1 || len(game.HomeRoster) != 0 {
+			t.Errorf("Final game changed by %s: %+v", name, game)
+		}
+	}
+}
+
+func TestPeriodPost(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1", Status: STATUS_SCHEDULED})
+
+	wt := webTest(t)
+	wt.post("game_id=CODE1&action=start")
+
+	periodPost(wt.ec)
+
+	wt.confirmRedirect("/game/CODE1")
+
+	game := dataStore.getGame(context.TODO(), "CODE1")
+	if game.Status != STATUS_IN_PROGRESS || game.Period != 1 {
+		t.Errorf("Unexpected game after period start: %s, P%d", game.Status, game.Period)
+	}
+}
+
+func TestPeriodPostOutOfOrder(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1", Status: STATUS_SCHEDULED})
+
+	end := webTest(t)
+	end.post("game_id=CODE1&action=end")
+	periodPost(end.ec)
+	end.confirmRedirect("/game/CODE1?e=8012")
+
+	first := webTest(t)
+	first.post("game_id=CODE1&action=start")
+	periodPost(first.ec)
+	first.confirmRedirect("/game/CODE1")
+
+	second := webTest(t)
+	second.post("game_id=CODE1&action=start")
+	periodPost(second.ec)
+	second.confirmRedirect("/game/CODE1?e=8012")
+
+	if game := dataStore.getGame(context.TODO(), "CODE1"); len(game.Events) != 1 || game.Events[0].EventType != PERIOD_START {
+		t.Errorf("Only the first period start should be recorded: %+v", game.Events)
+	}
+}
+
+func TestReopenGameNotFinal(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1)
+
+	reopenGamePost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_1)
+	if len(dataStore.getChangeLog(context.TODO(), TEST_ID_1).Changes) != 0 {
+		t.Error("Reopening a game that is not final should not be recorded")
+	}
+}
+
+func TestOfficialsPost(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&referee1=Ref+One&scorekeeper=Sam&home_coach=Coach+H")
+
+	officialsPost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_1)
+
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	if game.Officials.Referee1 != "Ref One" || game.Officials.Scorekeeper != "Sam" || game.HomeStaff.Coach != "Coach H" {
+		t.Errorf("Officials not recorded: %+v %+v", game.Officials, game.HomeStaff)
+	}
+}
+
+func TestSignGamePost(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_2 + "&role=Referee&signed_name=Ref+One&unlock_key=secret123")
+
+	signGamePost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_2)
+
+	game := dataStore.getGame(context.TODO(), TEST_ID_2)
+	signature := game.SignatureFor(SIGN_REFEREE)
+	if signature == nil || signature.Name != "Ref One" || signature.Signed.IsZero() {
+		t.Errorf("Signature not recorded: %v", game.Signatures)
+	}
+}
+
+func TestSignGamePostNeedsUnlockKey(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_2 + "&role=" + SIGN_HOME_COACH + "&signed_name=Anyone&unlock_key=guess")
+
+	signGamePost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_2 + "?e=8011")
+	if len(dataStore.getGame(context.TODO(), TEST_ID_2).Signatures) != 0 {
+		t.Error("Game should not be signed without its unlock key")
+	}
+}
+
+func TestDeleteEventRecordsChange(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&event_summary=01:30 Home Goal")
+
+	deleteEventPost(wt.ec)
+
+	log := dataStore.getChangeLog(context.TODO(), TEST_ID_1)
+	if len(log.Changes) != 1 || log.Changes[0].Before != "P1 18:30 Home Goal (Even) #41 A:#89 A:#93" {
+		t.Errorf("Deletion not recorded in change log: %+v", log.Changes)
+	}
+
+	page := webTest(t)
+	page.setParam("id", TEST_ID_1)
+	defer page.showBodyOnFail()
+
+	gameHistoryPage(page.ec)
+
+	page.confirmSuccessResponse()
+	page.confirmHtmlIncludes("#change_log", "Delete event")
+	if log.Changes[0].RemoteAddr == "" || strings.Contains(page.resp.Body.String(), log.Changes[0].RemoteAddr) {
+		t.Error("Editor's address should be recorded but not shown")
+	}
+}
+
+func TestLockGameAlreadyLocked(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("action=lock&item_type=game&item_code=" + TEST_ID_2 + "&unlock_key=mine")
+
+	lockItemPost(wt.ec)
+
+	wt.confirmRedirect("/lock?error=1003&action=Lock&type=Game&code=" + TEST_ID_2)
+	if game := dataStore.getGame(context.TODO(), TEST_ID_2); game.LockedWith != "secret123" {
+		t.Errorf("Lock key should not be replaced, got %s", game.LockedWith)
+	}
+}
+
+func TestLockSignedOffGame(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+	ctx := context.TODO()
+	game := dataStore.getGame(ctx, TEST_ID_2)
+	SignOff(&game, SIGN_HOME_COACH, "Coach H", time.Now())
+	SignOff(&game, SIGN_AWAY_COACH, "Coach A", time.Now())
+	dataStore.putGame(ctx, TEST_ID_2, game)
+
+	for _, action := range []string{"Unlock", "Lock"} {
+		wt := webTest(t)
+		wt.post("action=" + action + "&item_type=game&item_code=" + TEST_ID_2 + "&unlock_key=secret123")
+
+		lockItemPost(wt.ec)
+
+		wt.confirmRedirect("/lock?error=1004&action=" + action + "&type=Game&code=" + TEST_ID_2)
+	}
+	if game := dataStore.getGame(ctx, TEST_ID_2); game.LockedWith != "secret123" {
+		t.Errorf("Signed off game's key should not change, got %s", game.LockedWith)
+	}
+}
+
+func TestSignGamePostSignedOffWithoutKey(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+	ctx := context.TODO()
+	game := dataStore.getGame(ctx, TEST_ID_2)
+	game.LockedWith = ""
+	SignOff(&game, SIGN_HOME_COACH, "Coach H", time.Now())
+	SignOff(&game, SIGN_AWAY_COACH, "Coach A", time.Now())
+	dataStore.putGame(ctx, TEST_ID_2, game)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_2 + "&role=" + SIGN_REFEREE + "&signed_name=Anyone&unlock_key=")
+
+	signGamePost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_2 + "?e=8011")
+}
+
+func TestLockItemPostUnknownAction(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("item_type=game&item_code=" + TEST_ID_1 + "&unlock_key=abc")
+
+	err := lockItemPost(wt.ec)
+
+	if httpError, ok := err.(*echo.HTTPError); !ok || httpError.Code != http.StatusBadRequest {
+		t.Errorf("Expected bad request, got %v", err)
+	}
+	if len(dataStore.getChangeLog(context.TODO(), TEST_ID_1).Changes) != 0 {
+		t.Error("Game should not be changed by an unknown lock action")
+	}
+}
+
+func TestDeleteGameCanBeRestored(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("item_type=game&item_code=" + TEST_ID_1 + "&confirm_code=" + TEST_ID_1)
+	deleteItemPost(wt.ec)
+	wt.confirmRedirect("/deleted?type=game&code=" + TEST_ID_1)
+
+	page := webTest(t)
+	page.setParam("id", TEST_ID_1)
+	gamePage(page.ec)
+	page.confirmRedirect("/deleted?type=game&code=" + TEST_ID_1)
+
+	deleted := webTest(t)
+	deleted.setQuery("type", "game")
+	deleted.setQuery("code", TEST_ID_1)
+	defer deleted.showBodyOnFail()
+	deletedItemPage(deleted.ec)
+	deleted.confirmSuccessResponse()
+	deleted.confirmHtmlIncludes("#btn_restore", "")
+
+	restore := webTest(t)
+	restore.post("item_type=game&item_code=" + TEST_ID_1)
+	restoreItemPost(restore.ec)
+	restore.confirmRedirect("/game/" + TEST_ID_1)
+
+	if dataStore.getGame(context.TODO(), TEST_ID_1).ID != TEST_ID_1 {
+		t.Error("Game not restored")
+	}
+	log := dataStore.getChangeLog(context.TODO(), TEST_ID_1)
+	if len(log.Changes) != 2 || log.Changes[0].Action != "Delete game" || log.Changes[1].Action != "Restore game" {
+		t.Errorf("Deleting and restoring should both be recorded: %+v", log.Changes)
+	}
+}
+
+func TestRestoreEventToFinalGame(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	eventId := game.Events[0].ID
+	dataStore.deleteEvent(context.TODO(), &game, eventId)
+	game.Status = STATUS_FINAL
+	dataStore.putGame(context.TODO(), TEST_ID_1, game)
+
+	wt := webTest(t)
+	wt.post("item_type=event&item_code=" + eventCode(TEST_ID_1, eventId))
+	restoreItemPost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_1 + "?e=8005")
+	if len(dataStore.deletedEvents(context.TODO(), TEST_ID_1)) != 1 {
+		t.Error("Event should not be restored to a final game")
+	}
+}
+
+func TestDeletedEventShownOnGamePage(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&event_summary=01:30 Home Goal")
+	deleteEventPost(wt.ec)
+
+	page := webTest(t)
+	page.setParam("id", TEST_ID_1)
+	defer page.showBodyOnFail()
+	gamePage(page.ec)
+	page.confirmSuccessResponse()
+	page.confirmHtmlIncludes("#deleted_events", "P1 18:30 Home Goal")
+}
+
+func TestNewTimeoutPage(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setQuery("type", "AT")
+	wt.setQuery("game", TEST_ID_1)
+	defer wt.showBodyOnFail()
+
+	newEventPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("#event_type", "")
+	if strings.Contains(wt.resp.Body.String(), `id="player"`) {
+		t.Error("Timeout form should not ask for a player")
+	}
+}
+
+func TestAddPenaltyShotPost(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	penaltyId := game.Events[0].ID
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&period=2&minutes=14&seconds=25&event_type=Penalty+Shot&home_away=Home&player=41&outcome=Save&penalty_event_id=" + penaltyId)
+
+	addEventPost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_1)
+
+	game = dataStore.getGame(context.TODO(), TEST_ID_1)
+	shot := game.Events[len(game.Events)-1]
+	if shot.EventType != PENALTY_SHOT || shot.Outcome != PS_SAVE || shot.PenaltyEventID != penaltyId {
+		t.Errorf("Penalty shot not recorded: %+v", shot)
+	}
+}
+
+func TestAddGoalWithPlayersOnIce(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&period=1&minutes=5&seconds=0&event_type=Goal&home_away=Home&player=41&category=Even&home_on_ice=41&home_on_ice=89&away_on_ice=98")
+
+	addEventPost(wt.ec)
+
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	goal := game.Events[len(game.Events)-1]
+	if len(goal.HomeOnIce) != 2 || len(goal.AwayOnIce) != 1 || goal.HomeOnIce[1] != 89 {
+		t.Errorf("Players on ice not recorded: %+v", goal)
+	}
+}
+
+func TestNewGoalPageShowsSkaters(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setQuery("type", "HG")
+	wt.setQuery("game", TEST_ID_1)
+	defer wt.showBodyOnFail()
+
+	newEventPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("#home_on_ice", "#41")
+}
+
+func TestFaceoffPage(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setQuery("game", TEST_ID_1)
+	defer wt.showBodyOnFail()
+
+	faceoffPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("#btn_home_won", "Reds")
+}
+
+func TestAddFaceoffPost(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&period=1&minutes=12&seconds=0&home_centre=41&away_centre=98&end=Away&home_away=Away")
+
+	addFaceoffPost(wt.ec)
+
+	wt.confirmRedirect("/faceoff?game=" + TEST_ID_1)
+
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	faceoff := game.Events[len(game.Events)-1]
+	if faceoff.EventType != FACEOFF || faceoff.HomeAway != AWAY || faceoff.Player != 98 || faceoff.Opponent != 41 || faceoff.Zone != ZONE_DEFENSIVE {
+		t.Errorf("Faceoff not recorded: %+v", faceoff)
+	}
+}
+
+func TestAddFaceoffPostRejectsBadInput(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	for form, status := range map[string]int{
+		"game_id=NOSUCHGAME&period=1&end=Home&home_away=Home":          http.StatusNotFound,
+		"game_id=" + TEST_ID_1 + "&period=1&end=Middle&home_away=Home": http.StatusBadRequest,
+	} {
+		wt := webTest(t)
+		wt.post(form)
+
+		err := addFaceoffPost(wt.ec)
+
+		if httpError, ok := err.(*echo.HTTPError); !ok || httpError.Code != status {
+			t.Errorf("Expected %d for %s, got %v", status, form, err)
+		}
+	}
+	if dataStore.datastore.Exists(context.TODO(), GAMES_COLLECTION, "NOSUCHGAME") {
+		t.Error("Faceoff for an unknown game should not create it")
+	}
+}
+
+func TestListDisciplinePage(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setParam("id", TEST_LIST_ID)
+	defer wt.showBodyOnFail()
+
+	listDisciplinePage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("#discipline_report", "Smith, J")
+}
+
+func TestDisciplineRulesPost(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("list_id=" + TEST_LIST_ID + "&game_misconduct_games=2&match_penalty_games=4&pim_threshold=40&pim_threshold_games=1")
+
+	disciplineRulesPost(wt.ec)
+
+	wt.confirmRedirect("/list/" + TEST_LIST_ID + "/discipline")
+	rules := dataStore.getList(context.TODO(), TEST_LIST_ID).DisciplineRules()
+	if rules.GameMisconductGames != 2 || rules.MatchPenaltyGames != 4 || rules.PIMThreshold != 40 {
+		t.Errorf("Rules not saved: %+v", rules)
+	}
+
+	invalid := webTest(t)
+	invalid.post("list_id=" + TEST_LIST_ID + "&game_misconduct_games=x")
+	disciplineRulesPost(invalid.ec)
+	invalid.confirmRedirect("/list/" + TEST_LIST_ID + "/discipline?e=8007")
+}
+
+func TestAddPenaltyFromCatalogue(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&period=1&minutes=5&seconds=0&event_type=Penalty&home_away=Home&player=41&category=FIGHT")
+
+	addEventPost(wt.ec)
+
+	wt.confirmRedirect("/game/" + TEST_ID_1)
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	penalty := game.Events[len(game.Events)-1]
+	if penalty.Category != "Fighting" || penalty.PenaltyType != PENALTY_MAJOR || penalty.Minutes != 5 {
+		t.Errorf("Penalty not taken from catalogue: %+v", penalty)
+	}
+
+	unknown := webTest(t)
+	unknown.post("game_id=" + TEST_ID_1 + "&period=1&minutes=5&seconds=0&event_type=Penalty&home_away=Home&player=41&category=Offside")
+	addEventPost(unknown.ec)
+	unknown.confirmRedirect("/game/" + TEST_ID_1 + "?e=8008")
+}
+
+func TestNewPenaltyPageUsesCatalogue(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.setQuery("type", "HP")
+	wt.setQuery("game", TEST_ID_1)
+	defer wt.showBodyOnFail()
+
+	newEventPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("#category", "Hooking (Minor, 2 min)")
+}
+
+func TestAddMultiPartPenalty(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("game_id=" + TEST_ID_1 + "&period=1&minutes=5&seconds=0&event_type=Penalty&home_away=Home&player=41&category=FIGHT&additional_penalty=Game+Misconduct&coincidental=true")
+
+	addEventPost(wt.ec)
+
+	game := dataStore.getGame(context.TODO(), TEST_ID_1)
+	penalty := game.Events[len(game.Events)-1]
+	if penalty.Minutes != 25 || len(penalty.Components) != 1 || !penalty.Coincidental {
+		t.Errorf("Multi-part penalty not recorded: %+v", penalty)
+	}
+}
+
+func TestNewEventPageHasEventId(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	defer wt.showBodyOnFail()
+	wt.setQuery("game", TEST_ID_1)
+	wt.setQuery("type", "HG")
+
+	newEventPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.doc, _ = goquery.NewDocumentFromReader(bytes.NewReader(wt.resp.Body.Bytes()))
+	if id, _ := wt.doc.Find("#event_id").Attr("value"); id == "" {
+		t.Error("New event form should include an event ID")
+	}
+}
+
+func TestNewGoalHasNoPenaltyShotCategory(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	defer wt.showBodyOnFail()
+	wt.setQuery("game", TEST_ID_1)
+	wt.setQuery("type", "HG")
+
+	newEventPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	if wt.document().Find("#category option[value=Pen]").Length() != 0 {
+		wt.failed = true
+		t.Error("Penalty shot goals should be recorded as penalty shots, not as a goal category")
+	}
+}
+
+func TestAddTeamPlayerPostLowerCaseId(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("team_id=" + strings.ToLower(TEST_TEAM_ID) + "&player_number=99&player_name=New")
+
+	addTeamPlayerPost(wt.ec)
+
+	wt.confirmRedirect("/team/" + TEST_TEAM_ID)
+	team := dataStore.getTeam(context.TODO(), TEST_TEAM_ID)
+	if FindPlayer(team.Players, 99) == nil {
+		t.Error("Player not added to team")
+	}
+}
+
+func TestAddTeamPlayerPostUnknownTeam(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("team_id=NOSUCHTEAM&player_number=99")
+
+	err := addTeamPlayerPost(wt.ec)
+
+	if httpError, ok := err.(*echo.HTTPError); !ok || httpError.Code != http.StatusNotFound {
+		t.Errorf("Expected not found, got %v", err)
+	}
+	if dataStore.datastore.Exists(context.TODO(), TEAMS_COLLECTION, "NOSUCHTEAM") {
+		t.Error("Unknown team should not be created")
+	}
 }
@@ -47,6 +47,14 @@ func GetHistory(ctx context.Context, cookieValue string) []HistoryItem {
 					Summary:  list.Name,
 					UrlPath:  "/list/" + list.ID,
 				}
+			} else if strings.ToLower(itemType) == "team" {
+				team := dataStore.getTeam(ctx, id)
+				item = HistoryItem{
+					ItemType: itemType,
+					ItemCode: team.ID,
+					Summary:  team.Name,
+					UrlPath:  "/team/" + team.ID,
+				}
 			}
 
 			if item.Summary != "" {
@@ -68,3 +76,14 @@ func HistoryString(items []HistoryItem) string {
 	}
 	return strings.TrimSpace(text)
 }
+
+// Returns just the history items of the specified type.
+func HistoryOfType(items []HistoryItem, itemType string) []HistoryItem {
+	var matching []HistoryItem
+	for _, item := range items {
+		if strings.EqualFold(item.ItemType, itemType) {
+			matching = append(matching, item)
+		}
+	}
+	return matching
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Pages are shown in the language chosen with the language cookie, or otherwise the best
+// match for the browser's Accept-Language header. Text in the templates is wrapped with T,
+// e.g. {{T "Home Goal"}}, and looked up in the message catalogue for the language. The
+// English text is the key, so anything missing from a catalogue is shown in English. Where
+// the same English word needs different translations, the key starts with a context, e.g.
+// "link|Home" for the link to the home page, which is left out of the English text. Only keys
+// found in a catalogue have their context removed, so data shown with T, such as an error
+// with an item ID, is never cut short at a "|".
+
+const DEFAULT_LANGUAGE = "en"
+const LANGUAGE_COOKIE = "scoresheetlang"
+
+type MessageCatalogue map[string]string
+
+var MessageCatalogues = map[string]MessageCatalogue{
+	"en": {},
+	"fr": frenchMessages,
+}
+
+var LanguageNames = map[string]string{
+	"en": "English",
+	"fr": "Français",
+}
+
+type Language struct {
+	Code string
+	Name string
+}
+
+// Returns the supported languages, in order of their codes.
+func Languages() []Language {
+	var languages []Language
+	for code := range MessageCatalogues {
+		languages = append(languages, Language{Code: code, Name: LanguageNames[code]})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Code < languages[j].Code
+	})
+	return languages
+}
+
+func isLanguage(code string) bool {
+	_, ok := MessageCatalogues[code]
+	return ok
+}
+
+// Returns the text in the given language, formatted with the arguments if there are any.
+func Translate(lang string, text string, args ...any) string {
+	if message, ok := MessageCatalogues[lang][text]; ok {
+		text = message
+	} else if _, english, ok := strings.Cut(text, "|"); ok && isCatalogued(text) {
+		text = english
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Reports whether any catalogue has a message for the text.
+func isCatalogued(text string) bool {
+	for _, catalogue := range MessageCatalogues {
+		if _, ok := catalogue[text]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the T function used by the templates for a language.
+func translator(lang string) func(string, ...any) string {
+	return func(text string, args ...any) string {
+		return Translate(lang, text, args...)
+	}
+}
+
+// Chooses the language for a request.
+func negotiateLanguage(c echo.Context) string {
+	if cookie, err := c.Cookie(LANGUAGE_COOKIE); err == nil && isLanguage(cookie.Value) {
+		return cookie.Value
+	}
+	return acceptLanguage(c.Request().Header.Get("Accept-Language"))
+}
+
+// Returns the supported language with the highest quality in an Accept-Language header,
+// e.g. "fr-CA,fr;q=0.9,en;q=0.8". Regional variants use the main language.
+func acceptLanguage(header string) string {
+	best, bestQuality := DEFAULT_LANGUAGE, 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		code := strings.ToLower(strings.SplitN(strings.TrimSpace(fields[0]), "-", 2)[0])
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		if isLanguage(code) && quality > bestQuality {
+			best, bestQuality = code, quality
+		}
+	}
+	return best
+}
+
+func setLanguageCookie(lang string, c echo.Context) {
+	cookie := new(http.Cookie)
+	cookie.Name = LANGUAGE_COOKIE
+	cookie.Path = "/"
+	cookie.Value = lang
+	cookie.SameSite = http.SameSiteStrictMode
+	cookie.Secure = true
+	cookie.Expires = time.Now().Add(2 * 365 * time.Hour)
+	c.SetCookie(cookie)
+}
+
+func languageSet(c echo.Context) error {
+	lang := c.QueryParam("lang")
+
+	if isLanguage(lang) {
+		setLanguageCookie(lang, c)
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/")
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                        "en",
+		"fr":                      "fr",
+		"fr-CA,fr;q=0.9,en;q=0.8": "fr",
+		"en-GB,en;q=0.9,fr;q=0.8": "en",
+		"de-DE,de;q=0.9,fr;q=0.5": "fr",
+		"de,it":                   "en",
+		"en;q=0.5, FR;q=0.7":      "fr",
+	}
+	for header, expected := range tests {
+		if lang := acceptLanguage(header); lang != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, header, lang)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if Translate("fr", "Home Goal") != "But domicile" {
+		t.Errorf("Unexpected translation %s", Translate("fr", "Home Goal"))
+	}
+	if Translate("fr", "No such message") != "No such message" {
+		t.Error("Missing translations should use the English text")
+	}
+	if Translate("en", "link|Home") != "Home" || Translate("fr", "link|Home") != "Accueil" {
+		t.Error("Context should be left out of the English text")
+	}
+	if Translate("fr", "%d minutes", 4) != "4 minutes" || Translate("fr", "by %s", "#41") != "par #41" {
+		t.Error("Expected arguments to be formatted")
+	}
+	if Translate("fr", "event|%s %s", "Domicile", "But") != "But – Domicile" {
+		t.Errorf("Unexpected event description %s", Translate("fr", "event|%s %s", "Domicile", "But"))
+	}
+	if Translate("en", "event|%s %s", "Home", "Goal") != "Home Goal" {
+		t.Errorf("Unexpected event description %s", Translate("en", "event|%s %s", "Home", "Goal"))
+	}
+	if Translate("fr", "Item not found: EV1|EV2") != "Item not found: EV1|EV2" {
+		t.Error("Text that is not in a catalogue should be shown in full")
+	}
+}
+
+// Every piece of text marked for translation in the templates should be in each catalogue.
+func TestCataloguesComplete(t *testing.T) {
+	marked := regexp.MustCompile(`{{T "([^"]*)"`)
+	pages, _ := fs.Glob(templateFiles(false), "*.html")
+	for _, page := range pages {
+		content, _ := fs.ReadFile(templateFiles(false), page)
+		for _, match := range marked.FindAllStringSubmatch(string(content), -1) {
+			for _, language := range Languages() {
+				if _, ok := MessageCatalogues[language.Code][match[1]]; !ok && language.Code != DEFAULT_LANGUAGE {
+					t.Errorf("%s has no %s translation for %q", page, language.Code, match[1])
+				}
+			}
+		}
+	}
+}
+
+func TestErrorMessagesTranslated(t *testing.T) {
+	for code := 8001; code <= 8012; code++ {
+		message := errorMessage(fmt.Sprint(code))
+		if _, ok := frenchMessages[message]; !ok {
+			t.Errorf("No French translation for %q", message)
+		}
+	}
+}
+
+func TestGamePageInFrench(t *testing.T) {
+	wt := webTest(t)
+	wt.req.Header.Set("Accept-Language", "fr-FR,fr;q=0.9")
+	wt.setParam("id", TEST_ID_1)
+	defer wt.showBodyOnFail()
+
+	dataStore = GameStore{datastore: testDataStore()}
+	addTestGames(dataStore)
+
+	gamePage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("#btn_home_goal", "But domicile")
+	wt.confirmHtmlIncludes("h3", "Déroulement du match")
+	wt.confirmHtmlIncludes("#period_summary", "Buts domicile")
+	wt.confirmHtmlIncludes("#home_scoring", "#41 Smith, J")
+}
+
+func TestDisciplineErrorInFrench(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.req.Header.Set("Accept-Language", "fr")
+	wt.setParam("id", TEST_LIST_ID)
+	wt.setQuery("e", "8007")
+	defer wt.showBodyOnFail()
+
+	listDisciplinePage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("#error_message", "Les règles de suspension doivent être des nombres entiers")
+}
+
+func TestLanguageCookie(t *testing.T) {
+	wt := webTest(t)
+	wt.req.Header.Set("Accept-Language", "fr")
+	wt.req.AddCookie(&http.Cookie{Name: LANGUAGE_COOKIE, Value: "en"})
+	defer wt.showBodyOnFail()
+
+	homePage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	wt.confirmHtmlIncludes("h4", "Record new game")
+	if lang, _ := wt.doc.Find("html").Attr("lang"); lang != "en" {
+		t.Errorf("Unexpected page language %s", lang)
+	}
+}
+
+func TestLanguageSet(t *testing.T) {
+	wt := webTest(t)
+	wt.setQuery("lang", "fr")
+
+	languageSet(wt.ec)
+
+	wt.confirmRedirect("/")
+	if cookie := wt.resp.Header().Get("Set-Cookie"); !regexp.MustCompile(LANGUAGE_COOKIE + "=fr").MatchString(cookie) {
+		t.Errorf("Expected language cookie, got %s", cookie)
+	}
+
+	wt = webTest(t)
+	wt.setQuery("lang", "xx")
+
+	languageSet(wt.ec)
+
+	if wt.resp.Header().Get("Set-Cookie") != "" {
+		t.Error("Unknown language should not be saved")
+	}
+}
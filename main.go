@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"os"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -18,7 +20,15 @@ func main() {
 	dataStore = createDataStore()
 	dataStore.open()
 	defer dataStore.close()
-	setupDataStore(dataStore)
+	if err := dataStore.ping(context.Background()); err != nil {
+		logs.error("Datastore %s is not available: %v", dataStore.summary(), err)
+	} else {
+		setupDataStore(dataStore)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startSweeper(ctx, dataStore, time.Hour)
 
 	addr := defaultAddr
 	if p := os.Getenv("PORT"); p != "" {
@@ -26,10 +36,16 @@ func main() {
 	}
 	logs.info("Server listening on port %s", addr)
 
+	renderer, err := NewTemplate(templateReload())
+	if err != nil {
+		logs.error("Unable to load templates: %v", err)
+		os.Exit(2)
+	}
+
 	e := echo.New()
 	e.HideBanner = true
 
-	addRoutes(e)
+	addRoutes(e, renderer)
 
 	e.Start(addr)
 }
@@ -42,9 +58,9 @@ func runningOnGCloud() bool {
 func createDataStore() GameStore {
 	var store GameStore
 	if runningOnGCloud() {
-		store.datastore = fireDataStore()
+		store.datastore = MeasuredDataStore{fireDataStore()}
 	} else {
-		store.datastore = testDataStore()
+		store.datastore = MeasuredDataStore{testDataStore()}
 	}
 	return store
 }
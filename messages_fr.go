@@ -0,0 +1,264 @@
+package main
+
+// French translations of the user interface, keyed by the English text.
+var frenchMessages = MessageCatalogue{
+	// Page layout and home page
+	"Ice Hockey Scoresheet": "Feuille de match de hockey sur glace",
+	"Return to home page":   "Retour à l'accueil",
+	"This site uses first-party cookies for configuration and security.": "Ce site utilise ses propres cookies pour la configuration et la sécurité.",
+	"Learn more":                           "En savoir plus",
+	"Learn how we store and use your data": "Comment nous conservons et utilisons vos données",
+	"Help":                                 "Aide",
+	"link|Home":                            "Accueil",
+	"Something went wrong":                 "Une erreur s'est produite",
+	"Record new game":                      "Enregistrer un nouveau match",
+	"Record game and calculate scoring totals.": "Enregistrez un match et calculez les statistiques.",
+	"New game":           "Nouveau match",
+	"View existing game": "Voir un match",
+	"Game ID:":           "Code du match :",
+	"View game":          "Voir le match",
+	"Create new list":    "Créer une liste",
+	"Build lists of games for easy access and sharing.": "Regroupez des matchs pour les retrouver et les partager facilement.",
+	"New list":           "Nouvelle liste",
+	"View existing list": "Voir une liste",
+	"List ID:":           "Code de la liste :",
+	"View list":          "Voir la liste",
+	"Register new team":  "Inscrire une équipe",
+	"Keep a team roster to copy into new games.": "Conservez l'effectif d'une équipe pour le copier dans les nouveaux matchs.",
+	"New team":           "Nouvelle équipe",
+	"View existing team": "Voir une équipe",
+	"Team ID:":           "Code de l'équipe :",
+	"View team":          "Voir l'équipe",
+	"History":            "Historique",
+	"About this site:":   "À propos de ce site :",
+	"The Ice Hockey Scoresheet web application is designed to help score keepers at UK recreational ice hockey games fill in the EIHA score sheets.": "L'application Feuille de match de hockey sur glace aide les marqueurs des matchs de hockey amateur au Royaume-Uni à remplir les feuilles de match de l'EIHA.",
+	"Note that information entered into this site can be accessed by anyone with the relevant identifying code.":                                     "Les informations saisies sur ce site sont accessibles à toute personne disposant du code correspondant.",
+	"For more details, see the": "Pour plus de détails, consultez la",
+	"Help page":                 "page d'aide",
+	"GAME":                      "match",
+	"LIST":                      "liste",
+	"TEAM":                      "équipe",
+
+	"Skip to content": "Aller au contenu",
+
+	// Game page
+	"Scheduled":                     "Programmé",
+	"In progress":                   "En cours",
+	"Intermission":                  "Entracte",
+	"Final":                         "Terminé",
+	"running":                       "en marche",
+	"stopped":                       "arrêté",
+	"Stop clock":                    "Arrêter le chrono",
+	"Start clock":                   "Démarrer le chrono",
+	"Next period":                   "Période suivante",
+	"Turn off clock":                "Désactiver le chrono",
+	"Period:":                       "Période :",
+	"Time:":                         "Temps :",
+	"Seconds":                       "Secondes",
+	"Set clock":                     "Régler le chrono",
+	"Game record":                   "Déroulement du match",
+	"event|%s %s":                   "%[2]s – %[1]s",
+	"Home":                          "Domicile",
+	"Away":                          "Visiteurs",
+	"by %s":                         "par %s",
+	"Awarded for %s":                "Accordé pour %s",
+	"Assisted by %s":                "Assisté par %s",
+	"and %s":                        "et %s",
+	"%d minutes":                    "%d minutes",
+	"coincidental":                  "coïncidentes",
+	"Not on %s roster:":             "Absent de l'effectif (%s) :",
+	"Delete event":                  "Supprimer une action",
+	"Home Goal":                     "But domicile",
+	"Home Penalty":                  "Pénalité domicile",
+	"Away Goal":                     "But visiteurs",
+	"Away Penalty":                  "Pénalité visiteurs",
+	"Home Timeout":                  "Temps mort domicile",
+	"Away Timeout":                  "Temps mort visiteurs",
+	"Home Penalty Shot":             "Tir de pénalité domicile",
+	"Away Penalty Shot":             "Tir de pénalité visiteurs",
+	"Faceoffs":                      "Mises au jeu",
+	"Home Goalie Returned":          "Retour du gardien domicile",
+	"Pull Home Goalie":              "Sortir le gardien domicile",
+	"Away Goalie Returned":          "Retour du gardien visiteurs",
+	"Pull Away Goalie":              "Sortir le gardien visiteurs",
+	"End period":                    "Fin de période",
+	"Start period":                  "Début de période",
+	"Use game clock":                "Utiliser le chrono",
+	"Deleted events":                "Actions supprimées",
+	"Deleted %s":                    "Supprimé le %s",
+	"Restore":                       "Restaurer",
+	"Game summary":                  "Résumé du match",
+	"Period summary":                "Résumé par période",
+	"Total":                         "Total",
+	"OT":                            "Prol.",
+	"Home Goals":                    "Buts domicile",
+	"Away Goals":                    "Buts visiteurs",
+	"Home Penalties":                "Pénalités domicile",
+	"Away Penalties":                "Pénalités visiteurs",
+	"Timeouts":                      "Temps morts",
+	"Empty-net goals":               "Buts dans une cage vide",
+	"Penalty shots":                 "Tirs de pénalité",
+	"Faceoffs won":                  "Mises au jeu gagnées",
+	"PIM":                           "Min. de pén.",
+	"Power plays":                   "Supériorités numériques",
+	"Home Scoring":                  "Marqueurs domicile",
+	"Away Scoring":                  "Marqueurs visiteurs",
+	"Player":                        "Joueur",
+	"Goals":                         "Buts",
+	"Assists":                       "Passes",
+	"Minutes":                       "Minutes",
+	"FO":                            "MAJ",
+	"Home Team Roster":              "Effectif domicile",
+	"Away Team Roster":              "Effectif visiteurs",
+	"Number":                        "Numéro",
+	"Player Name":                   "Nom du joueur",
+	"Pos":                           "Pos",
+	"C/A":                           "C/A",
+	"Status":                        "Statut",
+	"Dressed":                       "En tenue",
+	"Scratched":                     "Retiré",
+	"starting goalie":               "gardien titulaire",
+	"Remove":                        "Retirer",
+	"No home team roster recorded.": "Aucun effectif domicile enregistré.",
+	"No away team roster recorded.": "Aucun effectif visiteurs enregistré.",
+	"Officials":                     "Officiels",
+	"Referees":                      "Arbitres",
+	"Linesmen":                      "Juges de ligne",
+	"Scorekeeper":                   "Marqueur",
+	"Timekeeper":                    "Chronométreur",
+	"Home coach":                    "Entraîneur domicile",
+	"Home manager":                  "Manager domicile",
+	"Away coach":                    "Entraîneur visiteurs",
+	"Away manager":                  "Manager visiteurs",
+	"Referee":                       "Arbitre",
+	"Sign-off":                      "Signatures",
+	"Role":                          "Rôle",
+	"Name":                          "Nom",
+	"Signed":                        "Signé",
+	"Signing as:":                   "Signer en tant que :",
+	"Name:":                         "Nom :",
+	"Sign scoresheet":               "Signer la feuille de match",
+	"Both coaches have signed, so the scoresheet can no longer be changed.": "Les deux entraîneurs ont signé, la feuille de match ne peut plus être modifiée.",
+	"The scoresheet can be signed once the game is final.":                  "La feuille de match peut être signée une fois le match terminé.",
+	"Add player":    "Ajouter un joueur",
+	"Share Game":    "Partager le match",
+	"Export":        "Exporter",
+	"Reopen game":   "Rouvrir le match",
+	"Finalise game": "Terminer le match",
+	"Unlock Game":   "Déverrouiller le match",
+	"Lock Game":     "Verrouiller le match",
+	"Delete game":   "Supprimer le match",
+
+	"Game clock":              "Chrono du match",
+	"Time":                    "Temps",
+	"Event":                   "Action",
+	"Empty net":               "Cage vide",
+	"No events recorded yet.": "Aucune action enregistrée pour le moment.",
+	"Record events":           "Enregistrer des actions",
+	"Team summary":            "Résumé par équipe",
+	"Plus/minus":              "Plus/moins",
+	"Game actions":            "Actions sur le match",
+
+	// Events
+	"Goal":            "But",
+	"Penalty":         "Pénalité",
+	"Timeout":         "Temps mort",
+	"Goalie Pulled":   "Gardien sorti",
+	"Goalie Returned": "Retour du gardien",
+	"Penalty Shot":    "Tir de pénalité",
+	"Faceoff":         "Mise au jeu",
+	"Period Start":    "Début de période",
+	"Period End":      "Fin de période",
+	"Even":            "À égalité",
+	"PP":              "SN",
+	"SH":              "IN",
+	"Pen":             "TP",
+	"EN":              "CV",
+	"Save":            "Arrêt",
+	"Miss":            "Manqué",
+
+	// New event form
+	"Clock Time:":                     "Temps au chrono :",
+	"Goalie:":                         "Gardien :",
+	"Player:":                         "Joueur :",
+	"Category:":                       "Catégorie :",
+	"Assists:":                        "Passes :",
+	"On ice:":                         "Sur la glace :",
+	"Outcome:":                        "Résultat :",
+	"Awarded for:":                    "Accordé pour :",
+	"Penalty:":                        "Pénalité :",
+	"Type:":                           "Type :",
+	"Usual for penalty":               "Habituel pour la pénalité",
+	"Plus:":                           "Plus :",
+	"Nothing else":                    "Rien d'autre",
+	"Coincidental:":                   "Coïncidentes :",
+	"Penalties from the %s rulebook.": "Pénalités selon le règlement %s.",
+	"Submit":                          "Valider",
+	"Note that this site does not currently support recording the time that delayed penalties occurred, or the time that penalties finished.": "Ce site ne permet pas encore d'enregistrer l'heure des pénalités différées, ni l'heure de fin des pénalités.",
+
+	"First assist":     "Première passe",
+	"Second assist":    "Deuxième passe",
+	"Not linked":       "Aucune",
+	"Choose a penalty": "Choisir une pénalité",
+
+	// Penalty types and penalties
+	"Minor":                   "Mineure",
+	"Bench Minor":             "Mineure de banc",
+	"Double Minor":            "Double mineure",
+	"Major":                   "Majeure",
+	"Misconduct":              "Méconduite",
+	"Game Misconduct":         "Méconduite pour le match",
+	"Match":                   "Pénalité de match",
+	"Abuse of Officials":      "Abus envers les officiels",
+	"Boarding":                "Mise en échec contre la bande",
+	"Butt-ending":             "Coup du bout du bâton",
+	"Charging":                "Charge",
+	"Checking to the Head":    "Mise en échec à la tête",
+	"Checking from Behind":    "Mise en échec par derrière",
+	"Cross-checking":          "Double-échec",
+	"Delaying the Game":       "Retarder le jeu",
+	"Delay of Game":           "Retarder le jeu",
+	"Diving":                  "Simulation",
+	"Elbowing":                "Coup de coude",
+	"Fighting":                "Bagarre",
+	"High-sticking":           "Bâton élevé",
+	"Holding":                 "Retenue",
+	"Holding the Stick":       "Retenir le bâton",
+	"Hooking":                 "Accrocher",
+	"Interference":            "Obstruction",
+	"Kicking":                 "Coup de pied",
+	"Kneeing":                 "Coup de genou",
+	"Roughing":                "Rudesse",
+	"Slashing":                "Cingler",
+	"Spearing":                "Darder",
+	"Too Many Players":        "Surnombre",
+	"Too Many Men":            "Surnombre",
+	"Tripping":                "Faire trébucher",
+	"Unsportsmanlike Conduct": "Conduite antisportive",
+	"Other":                   "Autre",
+
+	// Themes
+	"Simple":        "Simple",
+	"Retro":         "Rétro",
+	"Original":      "Original",
+	"High contrast": "Contraste élevé",
+	"Dark":          "Sombre",
+
+	// Error messages
+	"Unable to unlock game for editing":                                               "Impossible de déverrouiller le match pour le modifier",
+	"Player number must be a number":                                                  "Le numéro du joueur doit être un nombre",
+	"Unable to edit locked team":                                                      "Impossible de modifier une équipe verrouillée",
+	"Unable to edit locked list":                                                      "Impossible de modifier une liste verrouillée",
+	"Game is final, reopen it to make changes":                                        "Le match est terminé, rouvrez-le pour le modifier",
+	"Unable to sign the scoresheet, the game must be final and a name is required":    "Impossible de signer la feuille de match : le match doit être terminé et un nom est requis",
+	"Suspension rules must be whole numbers of zero or more":                          "Les règles de suspension doivent être des nombres entiers positifs ou nuls",
+	"Penalty is not in the penalty catalogue for this competition":                    "Cette pénalité ne figure pas dans le catalogue de la compétition",
+	"Penalty minutes do not match the type of penalty":                                "Les minutes de pénalité ne correspondent pas au type de pénalité",
+	"Unable to sign the scoresheet, the game must be locked and its unlock key given": "Impossible de signer la feuille de match, le match doit être verrouillé et sa clé fournie",
+	"Unlock key:": "Clé de déverrouillage :",
+	"Lock the game with a key to sign the scoresheet.":                                                               "Verrouillez le match avec une clé pour signer la feuille de match.",
+	"Period must be from 1 to 4, where 4 is overtime":                                                                "La période doit être comprise entre 1 et 4, la 4 étant la prolongation",
+	"Already locked, unlock it first to change the key":                                                              "Déjà verrouillé, déverrouillez-le d'abord pour changer la clé",
+	"Signed off games cannot be locked or unlocked":                                                                  "Les matchs signés ne peuvent pas être verrouillés ni déverrouillés",
+	"Periods can only be started when the game is scheduled or in an intermission, and ended when it is in progress": "Une période ne peut commencer que si le match est prévu ou à l'entracte, et ne peut finir que si le match est en cours",
+}
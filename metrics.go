@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Liveness, readiness and Prometheus metrics. The metrics are kept in memory and written in
+// the Prometheus text format, which is simple enough not to need the client library.
+
+const READY_TIMEOUT = 2 * time.Second
+
+// Upper bounds in seconds, the same as the Prometheus client's default buckets.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type Histogram struct {
+	Buckets []uint64 // Counts for each bucket in latencyBuckets, not cumulative
+	Count   uint64
+	Sum     float64
+}
+
+func (h *Histogram) observe(seconds float64) {
+	if h.Buckets == nil {
+		h.Buckets = make([]uint64, len(latencyBuckets))
+	}
+	for n, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.Buckets[n]++
+			break
+		}
+	}
+	h.Count++
+	h.Sum += seconds
+}
+
+// A set of counters or histograms, each identified by its label values.
+type Metrics struct {
+	mutex              sync.Mutex
+	requests           map[string]uint64
+	requestLatency     map[string]*Histogram
+	datastoreLatency   map[string]*Histogram
+	datastoreErrors    map[string]uint64
+	datastoreOperation map[string]uint64
+}
+
+var metrics = newMetrics()
+
+func newMetrics() *Metrics {
+	return &Metrics{
+		requests:           make(map[string]uint64),
+		requestLatency:     make(map[string]*Histogram),
+		datastoreLatency:   make(map[string]*Histogram),
+		datastoreErrors:    make(map[string]uint64),
+		datastoreOperation: make(map[string]uint64),
+	}
+}
+
+// Formats label names and values, e.g. `method="GET",route="/game/:id"`.
+func labels(pairs ...string) string {
+	var parts []string
+	for n := 0; n+1 < len(pairs); n += 2 {
+		parts = append(parts, pairs[n]+"="+strconv.Quote(pairs[n+1]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func observe(histograms map[string]*Histogram, key string, duration time.Duration) {
+	histogram, ok := histograms[key]
+	if !ok {
+		histogram = &Histogram{}
+		histograms[key] = histogram
+	}
+	histogram.observe(duration.Seconds())
+}
+
+func (m *Metrics) recordRequest(method string, route string, status int, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests[labels("method", method, "route", route, "status", strconv.Itoa(status))]++
+	observe(m.requestLatency, labels("method", method, "route", route), duration)
+}
+
+// Names a collection without the IDs in its path, e.g. "Changes/Entries" for the changes
+// to every game, so that there is one set of metrics for it rather than one for each game.
+func collectionLabel(collection string) string {
+	parts := strings.Split(collection, "/")
+	var names []string
+	for n := 0; n < len(parts); n += 2 {
+		names = append(names, parts[n])
+	}
+	return strings.Join(names, "/")
+}
+
+func (m *Metrics) recordDatastore(operation string, collection string, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	key := labels("operation", operation, "collection", collectionLabel(collection))
+	m.datastoreOperation[key]++
+	observe(m.datastoreLatency, key, duration)
+}
+
+func (m *Metrics) recordDatastoreError(operation string, collection string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.datastoreErrors[labels("operation", operation, "collection", collectionLabel(collection))]++
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeCounter(w io.Writer, name string, help string, values map[string]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s{%s} %d\n", name, key, values[key])
+	}
+}
+
+func writeHistogram(w io.Writer, name string, help string, values map[string]*Histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, key := range sortedKeys(values) {
+		histogram := values[key]
+		var cumulative uint64
+		for n, bound := range latencyBuckets {
+			cumulative += histogram.Buckets[n]
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%g\"} %d\n", name, key, bound, cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, key, histogram.Count)
+		fmt.Fprintf(w, "%s_sum{%s} %g\n", name, key, histogram.Sum)
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, key, histogram.Count)
+	}
+}
+
+// Writes all the metrics in the Prometheus text format.
+func (m *Metrics) write(w io.Writer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	writeCounter(w, "scoresheet_http_requests_total", "HTTP requests by route and status.", m.requests)
+	writeHistogram(w, "scoresheet_http_request_duration_seconds", "HTTP request latency by route.", m.requestLatency)
+	writeCounter(w, "scoresheet_datastore_operations_total", "Datastore operations by type and collection.", m.datastoreOperation)
+	writeHistogram(w, "scoresheet_datastore_operation_duration_seconds", "Datastore operation latency.", m.datastoreLatency)
+	writeCounter(w, "scoresheet_datastore_errors_total", "Datastore operations that failed.", m.datastoreErrors)
+}
+
+// Middleware that counts requests and their latency by route, e.g. "/game/:id" rather than
+// each game's own path.
+func requestMetrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+
+		status := c.Response().Status
+		if err != nil && !c.Response().Committed {
+			// Echo's error handler writes the response after this, as a 500 unless it is an HTTPError
+			status = http.StatusInternalServerError
+			if httpError, ok := err.(*echo.HTTPError); ok {
+				status = httpError.Code
+			}
+		}
+		route := c.Path()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.recordRequest(c.Request().Method, route, status, time.Since(start))
+		return err
+	}
+}
+
+// Times each datastore operation.
+type MeasuredDataStore struct {
+	DataStore
+}
+
+func measure(operation string, collection string, start time.Time) {
+	metrics.recordDatastore(operation, collection, time.Since(start))
+}
+
+func (store MeasuredDataStore) Get(ctx context.Context, collection string, id string, item interface{}) interface{} {
+	defer measure("get", collection, time.Now())
+	return store.DataStore.Get(ctx, collection, id, item)
+}
+
+func (store MeasuredDataStore) Put(ctx context.Context, collection string, id string, item interface{}) {
+	defer measure("put", collection, time.Now())
+	store.DataStore.Put(ctx, collection, id, item)
+}
+
+func (store MeasuredDataStore) Delete(ctx context.Context, collection string, id string) {
+	defer measure("delete", collection, time.Now())
+	store.DataStore.Delete(ctx, collection, id)
+}
+
+func (store MeasuredDataStore) Exists(ctx context.Context, collection string, id string) bool {
+	defer measure("exists", collection, time.Now())
+	return store.DataStore.Exists(ctx, collection, id)
+}
+
+func (store MeasuredDataStore) Keys(ctx context.Context, collection string) []string {
+	defer measure("keys", collection, time.Now())
+	return store.DataStore.Keys(ctx, collection)
+}
+
+func (store MeasuredDataStore) Update(ctx context.Context, collection string, id string, newItem func() interface{}, change func(item interface{}) error) error {
+	defer measure("update", collection, time.Now())
+	return store.DataStore.Update(ctx, collection, id, newItem, change)
+}
+
+func AddHealthHandlers(e *echo.Echo) {
+	e.GET("/healthz", healthz)
+	e.GET("/readyz", readyz)
+	e.GET("/metrics", metricsPage)
+}
+
+// The server is alive if it can answer at all.
+func healthz(c echo.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+
+// The server is ready when the datastore can be reached.
+func readyz(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), READY_TIMEOUT)
+	defer cancel()
+
+	if err := dataStore.ping(ctx); err != nil {
+		logs.error("Readiness check failed: %v", err)
+		return c.String(http.StatusServiceUnavailable, "datastore unavailable")
+	}
+	return c.String(http.StatusOK, "ready")
+}
+
+// Shows the metrics. If METRICS_TOKEN is set, the request must include it as a bearer token.
+func metricsPage(c echo.Context) error {
+	if token := os.Getenv("METRICS_TOKEN"); token != "" {
+		given := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			return c.NoContent(http.StatusUnauthorized)
+		}
+	}
+	c.Response().Header().Set(echo.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	metrics.write(c.Response())
+	return nil
+}
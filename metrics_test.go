@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type failingDataStore struct {
+	TestDataStore
+}
+
+func (store *failingDataStore) ping(ctx context.Context) error {
+	return errors.New("unavailable")
+}
+
+func healthServer() *echo.Echo {
+	e := echo.New()
+	e.Use(requestMetrics)
+	AddHealthHandlers(e)
+	e.GET("/game/:id", func(c echo.Context) error { return c.String(http.StatusOK, "game") })
+	return e
+}
+
+func TestHealthz(t *testing.T) {
+	resp := getPage(healthServer(), "/healthz")
+	if resp.Code != http.StatusOK || resp.Body.String() != "ok" {
+		t.Errorf("Unexpected health response %d %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestReadyWhenDatastoreAvailable(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+
+	resp := getPage(healthServer(), "/readyz")
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected ready, got %d", resp.Code)
+	}
+}
+
+func TestNotReadyWhenDatastoreUnavailable(t *testing.T) {
+	dataStore = GameStore{datastore: &failingDataStore{}}
+	defer func() { dataStore = GameStore{datastore: testDataStore()} }()
+
+	resp := getPage(healthServer(), "/readyz")
+	if resp.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", resp.Code)
+	}
+}
+
+func TestFireDataStoreNotReadyWithoutClient(t *testing.T) {
+	store := &FireDataStore{}
+	if store.ping(context.Background()) == nil {
+		t.Error("Store without a client should not be ready")
+	}
+	store.close()
+}
+
+func TestMetricsCountRequestsByRoute(t *testing.T) {
+	metrics = newMetrics()
+	e := healthServer()
+	getPage(e, "/game/abc")
+	getPage(e, "/game/def")
+	getPage(e, "/nosuchpage")
+
+	body := getPage(e, "/metrics").Body.String()
+	for _, expected := range []string{
+		`scoresheet_http_requests_total{method="GET",route="/game/:id",status="200"} 2`,
+		`scoresheet_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`scoresheet_http_request_duration_seconds_count{method="GET",route="/game/:id"} 2`,
+		`scoresheet_http_request_duration_seconds_bucket{method="GET",route="/game/:id",le="+Inf"} 2`,
+		"# TYPE scoresheet_datastore_operation_duration_seconds histogram",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Metrics should include %s\n%s", expected, body)
+		}
+	}
+}
+
+func TestMetricsCountHandlerErrors(t *testing.T) {
+	metrics = newMetrics()
+	e := healthServer()
+	e.GET("/broken", func(c echo.Context) error { return errors.New("broken") })
+	if resp := getPage(e, "/broken"); resp.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected response %d", resp.Code)
+	}
+
+	body := getPage(e, "/metrics").Body.String()
+	if !strings.Contains(body, `scoresheet_http_requests_total{method="GET",route="/broken",status="500"} 1`) {
+		t.Errorf("Handler error should be counted as a 500\n%s", body)
+	}
+}
+
+func TestMetricsDatastoreOperations(t *testing.T) {
+	metrics = newMetrics()
+	store := GameStore{datastore: MeasuredDataStore{testDataStore()}}
+	store.getGame(context.Background(), "nosuchgame")
+	metrics.recordDatastoreError("get", GAMES_COLLECTION)
+
+	var body strings.Builder
+	metrics.write(&body)
+	for _, expected := range []string{
+		`scoresheet_datastore_operations_total{operation="get",collection="Games"} 1`,
+		`scoresheet_datastore_operation_duration_seconds_count{operation="get",collection="Games"} 1`,
+		`scoresheet_datastore_errors_total{operation="get",collection="Games"} 1`,
+	} {
+		if !strings.Contains(body.String(), expected) {
+			t.Errorf("Metrics should include %s\n%s", expected, body.String())
+		}
+	}
+}
+
+func TestCollectionLabel(t *testing.T) {
+	if collectionLabel(changeEntriesCollection("GAME-1234")) != "Changes/Entries" {
+		t.Errorf("Unexpected label %s", collectionLabel(changeEntriesCollection("GAME-1234")))
+	}
+	if collectionLabel(GAMES_COLLECTION) != GAMES_COLLECTION {
+		t.Error("Top-level collection should be named as it is")
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	var histogram Histogram
+	histogram.observe((3 * time.Millisecond).Seconds())
+	histogram.observe(0.3)
+	histogram.observe(60)
+
+	if histogram.Buckets[0] != 1 || histogram.Buckets[6] != 1 || histogram.Count != 3 {
+		t.Errorf("Unexpected histogram %+v", histogram)
+	}
+}
+
+func TestMetricsToken(t *testing.T) {
+	t.Setenv("METRICS_TOKEN", "secret")
+	e := healthServer()
+
+	if resp := getPage(e, "/metrics"); resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", resp.Code)
+	}
+	if resp := getPage(e, "/metrics", "Authorization", "Bearer secret"); resp.Code != http.StatusOK {
+		t.Errorf("Expected 200 with token, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type Officials struct {
+	Referee1    string `form:"referee1"`
+	Referee2    string `form:"referee2"`
+	Linesman1   string `form:"linesman1"`
+	Linesman2   string `form:"linesman2"`
+	Scorekeeper string `form:"scorekeeper"`
+	Timekeeper  string `form:"timekeeper"`
+}
+
+type TeamStaff struct {
+	Coach   string
+	Manager string
+}
+
+// A Signature records that a coach or official has confirmed the final scoresheet.
+type Signature struct {
+	Role   string
+	Name   string
+	Signed time.Time
+}
+
+const SIGN_HOME_COACH = "Home coach"
+const SIGN_AWAY_COACH = "Away coach"
+const SIGN_REFEREE = "Referee"
+const SIGN_SCOREKEEPER = "Scorekeeper"
+
+var SignatureRoles = []string{SIGN_HOME_COACH, SIGN_AWAY_COACH, SIGN_REFEREE, SIGN_SCOREKEEPER}
+
+// Records a signature against the final scoresheet. Each role can only sign once.
+func SignOff(game *Game, role string, name string, now time.Time) error {
+	name = strings.TrimSpace(name)
+
+	if !game.IsFinal() {
+		return errors.New("game must be final before it can be signed")
+	}
+	if !validSignatureRole(role) {
+		return errors.New("unknown signature role")
+	}
+	if name == "" {
+		return errors.New("name is required to sign")
+	}
+	if game.SignatureFor(role) != nil {
+		return errors.New(role + " has already signed")
+	}
+
+	game.Signatures = append(game.Signatures, Signature{Role: role, Name: name, Signed: now})
+	return nil
+}
+
+func validSignatureRole(role string) bool {
+	for _, valid := range SignatureRoles {
+		if role == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the signature for a role, or nil if that role has not signed.
+func (game Game) SignatureFor(role string) *Signature {
+	for n := range game.Signatures {
+		if game.Signatures[n].Role == role {
+			return &game.Signatures[n]
+		}
+	}
+	return nil
+}
+
+// Returns true once both coaches have signed the scoresheet.
+func (game Game) SignedOff() bool {
+	return game.SignatureFor(SIGN_HOME_COACH) != nil && game.SignatureFor(SIGN_AWAY_COACH) != nil
+}
+
+// Returns the roles that have not yet signed the scoresheet.
+func (game Game) UnsignedRoles() []string {
+	var roles []string
+	for _, role := range SignatureRoles {
+		if game.SignatureFor(role) == nil {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
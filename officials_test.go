@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSignOff(t *testing.T) {
+	game := testGame1()
+	now := time.Now()
+
+	if SignOff(&game, SIGN_HOME_COACH, "Coach A", now) == nil {
+		t.Error("Game should not be signed before it is final")
+	}
+
+	game.Status = STATUS_FINAL
+
+	if SignOff(&game, "Mascot", "Bob", now) == nil {
+		t.Error("Unknown role should not be able to sign")
+	}
+	if SignOff(&game, SIGN_HOME_COACH, " ", now) == nil {
+		t.Error("Signature without a name should be rejected")
+	}
+
+	if err := SignOff(&game, SIGN_HOME_COACH, "Coach A", now); err != nil {
+		t.Errorf("Home coach could not sign: %v", err)
+	}
+	if SignOff(&game, SIGN_HOME_COACH, "Coach B", now) == nil {
+		t.Error("Home coach should not be able to sign twice")
+	}
+	if game.IsLocked() {
+		t.Error("Game should not be locked until both coaches sign")
+	}
+
+	SignOff(&game, SIGN_AWAY_COACH, "Coach Z", now)
+
+	if !game.SignedOff() || !game.IsLocked() {
+		t.Error("Game should be locked once both coaches sign")
+	}
+	if len(game.UnsignedRoles()) != 2 {
+		t.Errorf("Unexpected unsigned roles: %v", game.UnsignedRoles())
+	}
+
+	Reopen(&game)
+	if !game.IsFinal() {
+		t.Error("Signed game should not be reopened")
+	}
+}
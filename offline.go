@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Events recorded while the browser was offline are queued on the device and sent here in a
+// batch when the connection returns. Each event carries the ID generated by the browser, so
+// sending the same batch again does not add the events twice.
+type SyncRequest struct {
+	GameID string       `json:"game_id"`
+	Events []url.Values `json:"events"`
+}
+
+type SyncResult struct {
+	Added      []string          `json:"added"`
+	Duplicates []string          `json:"duplicates"`
+	Rejected   map[string]string `json:"rejected"` // Event ID to error message
+}
+
+// The service worker has to be served from the top level so that it can handle every page.
+func AddOfflineHandlers(e *echo.Echo, files fs.FS) {
+	e.POST("/syncEvents", syncEventsPost)
+	e.GET("/sw.js", func(c echo.Context) error {
+		return serveAsset(c, files, "sw.js", "no-cache")
+	})
+	e.GET("/manifest.webmanifest", func(c echo.Context) error {
+		return serveAsset(c, files, "manifest.webmanifest", SHORT_CACHE)
+	})
+}
+
+func syncEventsPost(c echo.Context) error {
+	var request SyncRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid sync request")
+	}
+
+	ctx := gctx(c)
+
+	game := dataStore.getGame(ctx, request.GameID)
+	if game.ID == "" || game.ID != request.GameID {
+		return echo.NewHTTPError(http.StatusNotFound, "Game not found")
+	}
+
+	result := SyncResult{Added: []string{}, Duplicates: []string{}, Rejected: map[string]string{}}
+
+	for _, form := range request.Events {
+		if game.IsLocked() {
+			result.Rejected[form.Get("event_id")] = errorMessage("8001")
+			continue
+		}
+		if game.IsFinal() {
+			result.Rejected[form.Get("event_id")] = errorMessage("8005")
+			continue
+		}
+
+		var event Event
+		if err := bindForm(c, form, &event); err != nil {
+			result.Rejected[form.Get("event_id")] = err.Error()
+			continue
+		}
+		if code := prepareEvent(game, &event, form); code != "" {
+			result.Rejected[form.Get("event_id")] = errorMessage(code)
+			continue
+		}
+
+		_, added, err := dataStore.addEvent(ctx, game.ID, event)
+		if err != nil {
+			result.Rejected[form.Get("event_id")] = err.Error()
+			continue
+		}
+		if added {
+			AddEvent(&game, event)
+			result.Added = append(result.Added, event.ID)
+			logGameChange(ctx, game.ID, "Add event", "", DescribeEvent(event)+" (synced)")
+		} else {
+			result.Duplicates = append(result.Duplicates, event.ID)
+		}
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Binds form values to an event in the same way as if they had been posted by the form.
+func bindForm(c echo.Context, form url.Values, event *Event) error {
+	req, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	binder := echo.DefaultBinder{}
+	return binder.BindBody(c.Echo().NewContext(req, nil), event)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func syncPost(t *testing.T, body string) (*WebTest, SyncResult) {
+	wt := webTest(t)
+	wt.req = httptest.NewRequest(http.MethodPost, "/syncEvents", strings.NewReader(body))
+	wt.req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	wt.ec = wt.e.NewContext(wt.req, wt.resp)
+
+	var result SyncResult
+	if err := syncEventsPost(wt.ec); err != nil {
+		wt.ec.Error(err)
+		return wt, result
+	}
+	if err := json.Unmarshal(wt.resp.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Could not read sync result: %v", err)
+	}
+	return wt, result
+}
+
+const SYNC_BATCH = `{"game_id": "CODE1", "events": [
+	{"event_id": ["ev-1"], "event_type": ["Goal"], "home_away": ["Home"], "period": ["1"], "minutes": ["12"], "seconds": ["30"], "player": ["41"]},
+	{"event_id": ["ev-2"], "event_type": ["Penalty"], "home_away": ["Away"], "period": ["1"], "minutes": ["10"], "seconds": ["0"], "player": ["7"], "category": ["Tripping"], "penaltyMinutes": ["2"]}
+]}`
+
+func TestSyncEvents(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1"})
+
+	_, result := syncPost(t, SYNC_BATCH)
+
+	if len(result.Added) != 2 || len(result.Duplicates) != 0 || len(result.Rejected) != 0 {
+		t.Errorf("Unexpected sync result %+v", result)
+	}
+	game := dataStore.getGame(context.TODO(), "CODE1")
+	if len(game.Events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(game.Events))
+	}
+	if game.Events[0].ID != "ev-1" || game.Events[0].ClockTime != "12:30" || game.Events[0].Player != 41 {
+		t.Errorf("Unexpected event %+v", game.Events[0])
+	}
+	if game.Events[1].Category != "Tripping" || game.Events[1].Minutes != 2 {
+		t.Errorf("Unexpected penalty %+v", game.Events[1])
+	}
+}
+
+func TestSyncEventsTwice(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1"})
+
+	syncPost(t, SYNC_BATCH)
+	_, result := syncPost(t, SYNC_BATCH)
+
+	if len(result.Added) != 0 || len(result.Duplicates) != 2 {
+		t.Errorf("Unexpected sync result %+v", result)
+	}
+	if game := dataStore.getGame(context.TODO(), "CODE1"); len(game.Events) != 2 {
+		t.Errorf("Expected 2 events after syncing twice, got %d", len(game.Events))
+	}
+}
+
+func TestSyncEventsRejected(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1", Competition: "IIHF"})
+
+	_, result := syncPost(t, `{"game_id": "CODE1", "events": [
+		{"event_id": ["ev-1"], "event_type": ["Penalty"], "home_away": ["Away"], "period": ["1"], "category": ["Not a penalty"], "penaltyMinutes": ["2"]}
+	]}`)
+
+	if result.Rejected["ev-1"] != errorMessage("8008") {
+		t.Errorf("Unexpected sync result %+v", result)
+	}
+	if game := dataStore.getGame(context.TODO(), "CODE1"); len(game.Events) != 0 {
+		t.Errorf("Rejected event was added")
+	}
+}
+
+func TestSyncEventsLockedGame(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1", LockedWith: "KEY"})
+
+	_, result := syncPost(t, SYNC_BATCH)
+
+	if len(result.Added) != 0 || len(result.Rejected) != 2 {
+		t.Errorf("Unexpected sync result %+v", result)
+	}
+}
+
+func TestSyncEventsUnknownGame(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+
+	wt, _ := syncPost(t, SYNC_BATCH)
+
+	if wt.resp.Code != http.StatusNotFound {
+		t.Errorf("Got status %d for unknown game", wt.resp.Code)
+	}
+}
+
+func TestAddEventPostTwice(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	dataStore.putGame(context.TODO(), "CODE1", Game{ID: "CODE1"})
+
+	for n := 0; n < 2; n++ {
+		wt := webTest(t)
+		wt.post("game_id=CODE1&event_id=abc-123&period=2&minutes=5&seconds=0")
+		addEventPost(wt.ec)
+		wt.confirmRedirect("/game/CODE1")
+	}
+
+	game := dataStore.getGame(context.TODO(), "CODE1")
+	if len(game.Events) != 1 || game.Events[0].ID != "abc-123" {
+		t.Errorf("Expected one event with the client ID, got %+v", game.Events)
+	}
+}
+
+func TestClientEventId(t *testing.T) {
+	if clientEventId("0b6c7a4e-1f2d-4c3b-9a8e-5d6f7a8b9c0d") != "0b6c7a4e-1f2d-4c3b-9a8e-5d6f7a8b9c0d" {
+		t.Error("Expected client event ID to be used")
+	}
+	for _, id := range []string{"", "<script>", strings.Repeat("A", MAX_EVENT_ID_LENGTH+1)} {
+		if clientEventId(id) == id {
+			t.Errorf("Expected a new ID instead of %q", id)
+		}
+	}
+}
+
+func TestServiceWorkerAtTopLevel(t *testing.T) {
+	e := echo.New()
+	AddOfflineHandlers(e, staticFiles(false))
+
+	resp := getPage(e, "/sw.js")
+	if resp.Code != http.StatusOK || resp.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("Unexpected service worker response %d %s", resp.Code, resp.Header().Get("Cache-Control"))
+	}
+
+	resp = getPage(e, "/manifest.webmanifest")
+	if resp.Header().Get(echo.HeaderContentType) != "application/manifest+json" {
+		t.Errorf("Unexpected manifest content type %s", resp.Header().Get(echo.HeaderContentType))
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+const PENALTY_MINOR = "Minor"
+const PENALTY_BENCH_MINOR = "Bench Minor"
+const PENALTY_DOUBLE_MINOR = "Double Minor"
+const PENALTY_MAJOR = "Major"
+const PENALTY_MISCONDUCT = "Misconduct"
+const PENALTY_GAME_MISCONDUCT = "Game Misconduct"
+const PENALTY_MATCH = "Match"
+
+// The minutes given for each type of penalty.
+var PenaltyTypeMinutes = map[string]int{
+	PENALTY_MINOR:           2,
+	PENALTY_BENCH_MINOR:     2,
+	PENALTY_DOUBLE_MINOR:    4,
+	PENALTY_MAJOR:           5,
+	PENALTY_MISCONDUCT:      10,
+	PENALTY_GAME_MISCONDUCT: 20,
+	PENALTY_MATCH:           25,
+}
+
+var PenaltyTypes = []string{PENALTY_MINOR, PENALTY_BENCH_MINOR, PENALTY_DOUBLE_MINOR, PENALTY_MAJOR,
+	PENALTY_MISCONDUCT, PENALTY_GAME_MISCONDUCT, PENALTY_MATCH}
+
+// A PenaltyDefinition is one infraction in a penalty catalogue, with the type of penalty
+// it usually receives.
+type PenaltyDefinition struct {
+	Code    string
+	Name    string
+	Type    string
+	Minutes int
+}
+
+type PenaltyCatalogue struct {
+	Name      string
+	Penalties []PenaltyDefinition
+}
+
+const DEFAULT_CATALOGUE = "IIHF"
+
+func penalty(code string, name string, penaltyType string) PenaltyDefinition {
+	return PenaltyDefinition{Code: code, Name: name, Type: penaltyType, Minutes: PenaltyTypeMinutes[penaltyType]}
+}
+
+var iihfPenalties = []PenaltyDefinition{
+	penalty("ABUSE", "Abuse of Officials", PENALTY_MISCONDUCT),
+	penalty("BOARD", "Boarding", PENALTY_MINOR),
+	penalty("BUTT", "Butt-ending", PENALTY_MAJOR),
+	penalty("CHARG", "Charging", PENALTY_MINOR),
+	penalty("CHEAD", "Checking to the Head", PENALTY_MAJOR),
+	penalty("CBEHIND", "Checking from Behind", PENALTY_MAJOR),
+	penalty("CROSS", "Cross-checking", PENALTY_MINOR),
+	penalty("DELAY", "Delaying the Game", PENALTY_MINOR),
+	penalty("DIVE", "Diving", PENALTY_MINOR),
+	penalty("ELBOW", "Elbowing", PENALTY_MINOR),
+	penalty("FIGHT", "Fighting", PENALTY_MAJOR),
+	penalty("HIGH", "High-sticking", PENALTY_MINOR),
+	penalty("HOLD", "Holding", PENALTY_MINOR),
+	penalty("HOLDS", "Holding the Stick", PENALTY_MINOR),
+	penalty("HOOK", "Hooking", PENALTY_MINOR),
+	penalty("INTRF", "Interference", PENALTY_MINOR),
+	penalty("KICK", "Kicking", PENALTY_MATCH),
+	penalty("KNEE", "Kneeing", PENALTY_MINOR),
+	penalty("ROUGH", "Roughing", PENALTY_MINOR),
+	penalty("SLASH", "Slashing", PENALTY_MINOR),
+	penalty("SPEAR", "Spearing", PENALTY_MAJOR),
+	penalty("TOOM", "Too Many Players", PENALTY_BENCH_MINOR),
+	penalty("TRIP", "Tripping", PENALTY_MINOR),
+	penalty("UNSP", "Unsportsmanlike Conduct", PENALTY_MINOR),
+	penalty("OTHER", "Other", PENALTY_MINOR),
+}
+
+// The EIHA rulebook follows the IIHF, but gives a match penalty for checking to the head
+// and a game misconduct for abuse of officials.
+var eihaPenalties = []PenaltyDefinition{
+	penalty("ABUSE", "Abuse of Officials", PENALTY_GAME_MISCONDUCT),
+	penalty("BOARD", "Boarding", PENALTY_MINOR),
+	penalty("BUTT", "Butt-ending", PENALTY_MAJOR),
+	penalty("CHARG", "Charging", PENALTY_MINOR),
+	penalty("CHEAD", "Checking to the Head", PENALTY_MATCH),
+	penalty("CBEHIND", "Checking from Behind", PENALTY_MAJOR),
+	penalty("CROSS", "Cross-checking", PENALTY_MINOR),
+	penalty("DELAY", "Delay of Game", PENALTY_MINOR),
+	penalty("DIVE", "Diving", PENALTY_MINOR),
+	penalty("ELBOW", "Elbowing", PENALTY_MINOR),
+	penalty("FIGHT", "Fighting", PENALTY_MAJOR),
+	penalty("HIGH", "High-sticking", PENALTY_MINOR),
+	penalty("HOLD", "Holding", PENALTY_MINOR),
+	penalty("HOLDS", "Holding the Stick", PENALTY_MINOR),
+	penalty("HOOK", "Hooking", PENALTY_MINOR),
+	penalty("INTRF", "Interference", PENALTY_MINOR),
+	penalty("KICK", "Kicking", PENALTY_MATCH),
+	penalty("KNEE", "Kneeing", PENALTY_MINOR),
+	penalty("ROUGH", "Roughing", PENALTY_MINOR),
+	penalty("SLASH", "Slashing", PENALTY_MINOR),
+	penalty("SPEAR", "Spearing", PENALTY_MAJOR),
+	penalty("TOOM", "Too Many Men", PENALTY_BENCH_MINOR),
+	penalty("TRIP", "Tripping", PENALTY_MINOR),
+	penalty("UNSP", "Unsportsmanlike Conduct", PENALTY_MINOR),
+	penalty("OTHER", "Other", PENALTY_MINOR),
+}
+
+var PenaltyCatalogues = map[string]PenaltyCatalogue{
+	"IIHF": {Name: "IIHF", Penalties: iihfPenalties},
+	"EIHA": {Name: "EIHA", Penalties: eihaPenalties},
+}
+
+// Returns the names of the penalty catalogues, to offer as competitions.
+func CatalogueNames() []string {
+	var names []string
+	for name := range PenaltyCatalogues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the penalty catalogue for a competition. Competitions that name a catalogue,
+// e.g. "EIHA Division 1", use that catalogue and all others use the IIHF rules.
+func CatalogueFor(competition string) PenaltyCatalogue {
+	upper := strings.ToUpper(competition)
+	for _, name := range CatalogueNames() {
+		if strings.Contains(upper, name) {
+			return PenaltyCatalogues[name]
+		}
+	}
+	return PenaltyCatalogues[DEFAULT_CATALOGUE]
+}
+
+// Finds a penalty by its code or name.
+func (catalogue PenaltyCatalogue) Find(codeOrName string) *PenaltyDefinition {
+	for n, penalty := range catalogue.Penalties {
+		if strings.EqualFold(penalty.Code, codeOrName) || strings.EqualFold(penalty.Name, codeOrName) {
+			return &catalogue.Penalties[n]
+		}
+	}
+	return nil
+}
+
+const PENALTY_UNKNOWN = "unknown"
+const PENALTY_BAD_MINUTES = "minutes"
+
+// Checks a new penalty against the catalogue, filling in the infraction name, the type of
+// penalty and the minutes for that type and any extra parts. Penalties use the catalogue's
+// usual type unless another is given. Returns a problem code if the penalty is not valid.
+func (catalogue PenaltyCatalogue) Validate(event *Event) string {
+	definition := catalogue.Find(event.Category)
+	if definition == nil {
+		return PENALTY_UNKNOWN
+	}
+	if event.PenaltyType == "" {
+		event.PenaltyType = definition.Type
+	}
+	minutes, ok := PenaltyTypeMinutes[event.PenaltyType]
+	if !ok {
+		return PENALTY_UNKNOWN
+	}
+	for n, component := range event.Components {
+		componentMinutes, ok := PenaltyTypeMinutes[component.Type]
+		if !ok {
+			return PENALTY_UNKNOWN
+		}
+		event.Components[n].Minutes = componentMinutes
+		minutes += componentMinutes
+	}
+	if event.Minutes != 0 && event.Minutes != minutes {
+		return PENALTY_BAD_MINUTES
+	}
+	event.Category = definition.Name
+	event.Minutes = minutes
+	return ""
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCatalogueFor(t *testing.T) {
+	if CatalogueFor("EIHA Division 1 North").Name != "EIHA" {
+		t.Error("Expected EIHA catalogue for an EIHA competition")
+	}
+	if CatalogueFor("").Name != DEFAULT_CATALOGUE || CatalogueFor("World Championship").Name != "IIHF" {
+		t.Error("Expected IIHF catalogue by default")
+	}
+}
+
+func TestCatalogueFind(t *testing.T) {
+	catalogue := CatalogueFor("IIHF")
+	if penalty := catalogue.Find("hook"); penalty == nil || penalty.Name != "Hooking" {
+		t.Errorf("Penalty not found by code: %v", penalty)
+	}
+	if penalty := catalogue.Find("Tripping"); penalty == nil || penalty.Code != "TRIP" {
+		t.Errorf("Penalty not found by name: %v", penalty)
+	}
+	if catalogue.Find("Offside") != nil {
+		t.Error("Unexpected penalty found")
+	}
+}
+
+func TestValidatePenalty(t *testing.T) {
+	catalogue := CatalogueFor("IIHF")
+
+	event := Event{EventType: PENALTY, Category: "HOOK"}
+	if problem := catalogue.Validate(&event); problem != "" {
+		t.Errorf("Unexpected problem: %s", problem)
+	}
+	if event.Category != "Hooking" || event.PenaltyType != PENALTY_MINOR || event.Minutes != 2 {
+		t.Errorf("Penalty not filled in from catalogue: %+v", event)
+	}
+
+	event = Event{EventType: PENALTY, Category: "HOOK", PenaltyType: PENALTY_MAJOR}
+	catalogue.Validate(&event)
+	if event.Minutes != 5 {
+		t.Errorf("Expected 5 minutes for a major, got %d", event.Minutes)
+	}
+
+	event = Event{EventType: PENALTY, Category: "HOOK", Minutes: 7}
+	if catalogue.Validate(&event) != PENALTY_BAD_MINUTES {
+		t.Error("Minutes that do not match the penalty type were accepted")
+	}
+
+	event = Event{EventType: PENALTY, Category: "Offside"}
+	if catalogue.Validate(&event) != PENALTY_UNKNOWN {
+		t.Error("Penalty not in the catalogue was accepted")
+	}
+}
+
+func TestCompetitionCatalogueDiffers(t *testing.T) {
+	iihf := CatalogueFor("IIHF").Find("CHEAD")
+	eiha := CatalogueFor("EIHA").Find("CHEAD")
+	if iihf.Type != PENALTY_MAJOR || eiha.Type != PENALTY_MATCH {
+		t.Errorf("Unexpected checking to the head penalties: %v, %v", iihf, eiha)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+)
+
+const PENALTY_SHOT = "Penalty Shot"
+
+const PS_GOAL = "Goal"
+const PS_SAVE = "Save"
+const PS_MISS = "Miss"
+
+var PenaltyShotOutcomes = []string{PS_GOAL, PS_SAVE, PS_MISS}
+
+func validOutcome(outcome string) bool {
+	for _, valid := range PenaltyShotOutcomes {
+		if outcome == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the penalty that a penalty shot was awarded for, or nil if it was not linked
+// to a penalty by the opposing team.
+func LinkedPenalty(game Game, shot Event) *Event {
+	if shot.PenaltyEventID == "" {
+		return nil
+	}
+	for n, event := range game.Events {
+		if event.ID == shot.PenaltyEventID && event.EventType == PENALTY && event.HomeAway != shot.HomeAway {
+			return &game.Events[n]
+		}
+	}
+	return nil
+}
+
+// Returns the penalties that could have led to a penalty shot for the team, i.e. those
+// given to the opposing team.
+func (game Game) PenaltiesAgainst(homeAway string) []EventSummary {
+	var penalties []EventSummary
+	for _, event := range game.Events {
+		if event.EventType == PENALTY && event.HomeAway == opposingTeam(homeAway) {
+			penalties = append(penalties, summariseEvent(game, event))
+		}
+	}
+	return penalties
+}
+
+// Adds a penalty shot to the summary. Only a scored penalty shot changes the score and it
+// is credited to the shooter as a goal without assists.
+func countPenaltyShot(summary *GameSummary, event Event) {
+	players := summary.HomePlayers
+	taken := &summary.HomePenaltyShots
+	scored := &summary.HomePenaltyShotGoals
+	if event.HomeAway == AWAY {
+		players = summary.AwayPlayers
+		taken = &summary.AwayPenaltyShots
+		scored = &summary.AwayPenaltyShotGoals
+	}
+
+	*taken++
+	player := players[event.Player]
+	player.PenaltyShots++
+	players[event.Player] = player
+
+	if event.Outcome != PS_GOAL {
+		return
+	}
+
+	*scored++
+	if event.HomeAway == HOME {
+		summary.HomeGoals++
+		summary.Periods[event.Period-1].HomeGoals++
+		summary.Periods[GAME_TOTAL].HomeGoals++
+	} else {
+		summary.AwayGoals++
+		summary.Periods[event.Period-1].AwayGoals++
+		summary.Periods[GAME_TOTAL].AwayGoals++
+	}
+	countPlayerEvent(event.Player, players, 1, 0, 0)
+	player = players[event.Player]
+	player.PenaltyShotGoals++
+	players[event.Player] = player
+}
+
+// Checks that a penalty shot has a shooter, an outcome and a penalty it was awarded for.
+func validatePenaltyShot(game Game, event Event, when string) []string {
+	var problems []string
+	if event.Player == 0 {
+		problems = append(problems, fmt.Sprintf("%s penalty shot at %s has no shooter", event.HomeAway, when))
+	}
+	if !validOutcome(event.Outcome) {
+		problems = append(problems, fmt.Sprintf("%s penalty shot at %s has no outcome", event.HomeAway, when))
+	}
+	if LinkedPenalty(game, event) == nil {
+		problems = append(problems, fmt.Sprintf("%s penalty shot at %s is not linked to a penalty", event.HomeAway, when))
+	}
+	return problems
+}
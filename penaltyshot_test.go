@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+)
+
+func addPenaltyShot(game *Game, period int, clockTime EventTime, homeAway string, player int, penaltyId string, outcome string) {
+	AddEvent(game, Event{
+		ID:             randomEventId(),
+		Period:         period,
+		ClockTime:      clockTime,
+		GameTime:       ClockToGameTime(period, clockTime),
+		HomeAway:       homeAway,
+		EventType:      PENALTY_SHOT,
+		Player:         player,
+		PenaltyEventID: penaltyId,
+		Outcome:        outcome,
+	})
+}
+
+func TestLinkedPenalty(t *testing.T) {
+	game := testGame1()
+	awayPenalty := game.Events[0]
+
+	shot := Event{HomeAway: HOME, PenaltyEventID: awayPenalty.ID}
+	if penalty := LinkedPenalty(game, shot); penalty == nil || penalty.ID != awayPenalty.ID {
+		t.Errorf("Penalty shot not linked to penalty: %v", penalty)
+	}
+
+	shot.HomeAway = AWAY
+	if LinkedPenalty(game, shot) != nil {
+		t.Error("Penalty shot linked to a penalty against its own team")
+	}
+}
+
+func TestPenaltyShotInSummary(t *testing.T) {
+	game := testGame1()
+	penaltyId := game.Events[0].ID
+	addPenaltyShot(&game, 2, "14:25", HOME, 41, penaltyId, PS_GOAL)
+	addPenaltyShot(&game, 3, "05:00", HOME, 89, penaltyId, PS_SAVE)
+
+	summary := summarise(game)
+
+	if summary.HomeGoals != 2 || summary.Periods[1].HomeGoals != 1 {
+		t.Errorf("Scored penalty shot not counted as a goal: %d", summary.HomeGoals)
+	}
+	if summary.HomePenaltyShots != 2 || summary.HomePenaltyShotGoals != 1 {
+		t.Errorf("Unexpected penalty shots: %d scored of %d", summary.HomePenaltyShotGoals, summary.HomePenaltyShots)
+	}
+	scorer := summary.HomePlayers[41]
+	if scorer.Goals != 2 || scorer.PenaltyShotGoals != 1 || scorer.Assists != 0 {
+		t.Errorf("Unexpected scorer summary: %+v", scorer)
+	}
+	if summary.HomePlayers[89].PenaltyShots != 1 || summary.HomePlayers[89].Goals != 0 {
+		t.Errorf("Saved penalty shot counted as a goal: %+v", summary.HomePlayers[89])
+	}
+	if summary.Events[4].PenaltyLabel != "P2 14:25 Away Penalty (Slash) #50 2 min" {
+		t.Errorf("Unexpected penalty label: %s", summary.Events[4].PenaltyLabel)
+	}
+}
+
+func TestValidatePenaltyShot(t *testing.T) {
+	game := testGame1()
+	addPenaltyShot(&game, 2, "14:25", HOME, 0, "", "")
+
+	problems := validatePenaltyShot(game, game.Events[4], "P2 14:25")
+	if len(problems) != 3 {
+		t.Errorf("Unexpected problems: %v", problems)
+	}
+}
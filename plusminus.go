@@ -0,0 +1,59 @@
+package main
+
+// Returns true if a goal counts towards plus/minus. Power play goals and goals from
+// penalty shots do not count. Penalty shots are now recorded as their own event, but older
+// games may have goals with the "Pen" category.
+func countsForPlusMinus(event Event) bool {
+	return event.EventType == GOAL && event.Category != POWER_PLAY && event.Category != "Pen"
+}
+
+// Returns the players recorded as on the ice for one side when the event happened.
+func (event Event) OnIce(homeAway string) []int {
+	if homeAway == HOME {
+		return event.HomeOnIce
+	}
+	return event.AwayOnIce
+}
+
+// Adds the plus/minus for a goal to the on-ice skaters of both teams. Goaltenders do not
+// have a plus/minus.
+func countPlusMinus(summary *GameSummary, game Game, event Event) {
+	if !countsForPlusMinus(event) || game.Roster(event.HomeAway) == nil {
+		return
+	}
+	scoring, conceding := summary.HomePlayers, summary.AwayPlayers
+	if event.HomeAway == AWAY {
+		scoring, conceding = summary.AwayPlayers, summary.HomePlayers
+	}
+	against := opposingTeam(event.HomeAway)
+
+	addPlusMinus(scoring, *game.Roster(event.HomeAway), event.OnIce(event.HomeAway), 1)
+	addPlusMinus(conceding, *game.Roster(against), event.OnIce(against), -1)
+}
+
+func addPlusMinus(players map[int]PlayerSummary, roster []Player, onIce []int, change int) {
+	for _, number := range onIce {
+		if player := FindPlayer(roster, number); player != nil && player.Position == "G" {
+			continue
+		}
+		summary := players[number]
+		summary.PlusMinus += change
+		players[number] = summary
+	}
+}
+
+// Returns the skaters on the roster that can be picked as being on the ice, leaving out
+// goaltenders and scratched players.
+func (game Game) Skaters(homeAway string) []Player {
+	roster := game.Roster(homeAway)
+	if roster == nil {
+		return nil
+	}
+	var skaters []Player
+	for _, player := range *roster {
+		if !player.Scratched && player.Position != "G" {
+			skaters = append(skaters, player)
+		}
+	}
+	return skaters
+}
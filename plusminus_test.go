@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestPlusMinus(t *testing.T) {
+	game := testGame1()
+	AddPlayer(&game, AWAY, Player{Number: 1, Name: "Goalie", Position: "G"})
+
+	AddEvent(&game, Event{ID: "E1", Period: 1, ClockTime: "10:00", EventType: GOAL, HomeAway: HOME, Player: 41, Category: "Even",
+		HomeOnIce: []int{41, 89}, AwayOnIce: []int{1, 98}})
+	AddEvent(&game, Event{ID: "E2", Period: 1, ClockTime: "09:00", EventType: GOAL, HomeAway: HOME, Player: 89, Category: POWER_PLAY,
+		HomeOnIce: []int{41, 89}, AwayOnIce: []int{98}})
+	AddEvent(&game, Event{ID: "E3", Period: 2, ClockTime: "09:00", EventType: GOAL, HomeAway: AWAY, Player: 98, Category: SHORT_HANDED,
+		HomeOnIce: []int{89}, AwayOnIce: []int{98}})
+
+	summary := summarise(game)
+
+	if summary.HomePlayers[41].PlusMinus != 1 {
+		t.Errorf("Expected +1 for #41, got %d", summary.HomePlayers[41].PlusMinus)
+	}
+	if summary.HomePlayers[89].PlusMinus != 0 {
+		t.Errorf("Expected 0 for #89, got %d", summary.HomePlayers[89].PlusMinus)
+	}
+	if summary.AwayPlayers[98].PlusMinus != 0 {
+		t.Errorf("Expected 0 for #98, got %d", summary.AwayPlayers[98].PlusMinus)
+	}
+	if _, ok := summary.AwayPlayers[1]; ok {
+		t.Error("Goaltender given a plus/minus")
+	}
+}
+
+func TestSkaters(t *testing.T) {
+	game := testGame1()
+	AddPlayer(&game, HOME, Player{Number: 30, Name: "Goalie", Position: "G"})
+	AddPlayer(&game, HOME, Player{Number: 77, Name: "Scratch", Scratched: true})
+
+	skaters := game.Skaters(HOME)
+	for _, player := range skaters {
+		if player.Position == "G" || player.Scratched {
+			t.Errorf("Unexpected skater: %+v", player)
+		}
+	}
+	if len(skaters) == 0 || skaters[0].Number != 41 {
+		t.Errorf("Unexpected skaters: %v", skaters)
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DELETED_COLLECTION = "Deleted"
+
+// Deleted events are kept in a collection for each game, under a document for the game in
+// this collection, so that a game's deleted events can be found without reading every item
+// in the recycle bin.
+const DELETED_EVENTS_COLLECTION = "DeletedEvents"
+const DELETED_EVENTS = "Events"
+
+const DEFAULT_RETENTION_DAYS = 30
+
+var errNotDeleted = errors.New("not in the recycle bin")
+
+// A DeletedItem holds a copy of a deleted game, list, team or event in the recycle bin
+// until it is either restored or purged.
+type DeletedItem struct {
+	ItemType string
+	ItemCode string
+	GameID   string
+	Summary  string
+	Deleted  time.Time
+	Data     string
+}
+
+// Returns how long deleted items are kept, from the DELETE_RETENTION_DAYS environment variable.
+func retentionPeriod() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("DELETE_RETENTION_DAYS"))
+	if err != nil || days < 1 {
+		days = DEFAULT_RETENTION_DAYS
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (item DeletedItem) Expires() time.Time {
+	return item.Deleted.Add(retentionPeriod())
+}
+
+// Returns the recycle bin document ID for an item. Events are identified by their game
+// as well as their own ID.
+func deletedKey(itemType string, itemCode string) string {
+	return strings.ToLower(itemType) + ":" + itemCode
+}
+
+func eventCode(gameId string, eventId string) string {
+	return gameId + ":" + eventId
+}
+
+func deletedEventsCollection(gameId string) string {
+	return DELETED_EVENTS_COLLECTION + "/" + gameId + "/" + DELETED_EVENTS
+}
+
+// Returns the collection and document ID for an item in the recycle bin.
+func deletedLocation(itemType string, code string) (string, string) {
+	if strings.ToLower(itemType) == "event" {
+		gameId, eventId, _ := strings.Cut(code, ":")
+		return deletedEventsCollection(gameId), eventId
+	}
+	return DELETED_COLLECTION, deletedKey(itemType, code)
+}
+
+// Moves an item into the recycle bin.
+func (store GameStore) softDelete(ctx context.Context, itemType string, id string) bool {
+	itemType = strings.ToLower(itemType)
+	collection, ok := Collections[itemType]
+	if !ok || !store.datastore.Exists(ctx, collection, id) {
+		return false
+	}
+
+	item := DeletedItem{
+		ItemType: itemType,
+		ItemCode: id,
+		Deleted:  time.Now(),
+	}
+
+	var data []byte
+	switch itemType {
+	case "game":
+		game := store.getGame(ctx, id)
+		item.Summary = game.Title
+		data, _ = json.Marshal(game)
+	case "list":
+		list := store.getList(ctx, id)
+		item.Summary = list.Name
+		data, _ = json.Marshal(list)
+	case "team":
+		team := store.getTeam(ctx, id)
+		item.Summary = team.Name
+		data, _ = json.Marshal(team)
+	}
+	item.Data = string(data)
+
+	store.datastore.Put(ctx, DELETED_COLLECTION, deletedKey(itemType, id), item)
+	store.datastore.Delete(ctx, collection, id)
+	return true
+}
+
+// Removes an event from a game and moves it into the recycle bin.
+func (store GameStore) deleteEvent(ctx context.Context, game *Game, eventId string) (Event, bool) {
+	event, found := RemoveEvent(game, eventId)
+	if found {
+		store.binEvent(ctx, game.ID, event)
+	}
+	return event, found
+}
+
+// Puts an event that has been removed from a game into the recycle bin. This is done once
+// the game has been saved without it, as the recycle bin is not part of the game's update.
+func (store GameStore) binEvent(ctx context.Context, gameId string, event Event) {
+	data, _ := json.Marshal(event)
+	item := DeletedItem{
+		ItemType: "event",
+		ItemCode: eventCode(gameId, event.ID),
+		GameID:   gameId,
+		Summary:  DescribeEvent(event),
+		Deleted:  time.Now(),
+		Data:     string(data),
+	}
+	collection, key := deletedLocation(item.ItemType, item.ItemCode)
+	store.datastore.Put(ctx, DELETED_EVENTS_COLLECTION, gameId, DeletedItem{GameID: gameId})
+	store.datastore.Put(ctx, collection, key, item)
+}
+
+func (store GameStore) getDeleted(ctx context.Context, itemType string, code string) DeletedItem {
+	var item DeletedItem
+	collection, key := deletedLocation(itemType, code)
+	store.datastore.Get(ctx, collection, key, &item)
+	return item
+}
+
+// Returns the events deleted from a game that are still in the recycle bin.
+func (store GameStore) deletedEvents(ctx context.Context, gameId string) []DeletedItem {
+	var items []DeletedItem
+	collection := deletedEventsCollection(gameId)
+	for _, key := range store.datastore.Keys(ctx, collection) {
+		var item DeletedItem
+		store.datastore.Get(ctx, collection, key, &item)
+		items = append(items, item)
+	}
+	return items
+}
+
+// Restores an item from the recycle bin. Events are added back into their game, unless it is
+// locked or final, when an ErrorCode is returned.
+func (store GameStore) restore(ctx context.Context, itemType string, code string) (DeletedItem, error) {
+	itemType = strings.ToLower(itemType)
+	item := store.getDeleted(ctx, itemType, code)
+	if item.ItemCode != code {
+		return item, errNotDeleted
+	}
+
+	var err error
+	switch itemType {
+	case "game":
+		var game Game
+		if err = json.Unmarshal([]byte(item.Data), &game); err == nil {
+			store.putGame(ctx, code, game)
+		}
+	case "list":
+		var list GameList
+		if err = json.Unmarshal([]byte(item.Data), &list); err == nil {
+			store.putList(ctx, code, list)
+		}
+	case "team":
+		var team Team
+		if err = json.Unmarshal([]byte(item.Data), &team); err == nil {
+			store.putTeam(ctx, code, team)
+		}
+	case "event":
+		var event Event
+		if err = json.Unmarshal([]byte(item.Data), &event); err == nil {
+			_, err = store.updateGame(ctx, item.GameID, func(game *Game) error {
+				if game.IsLocked() {
+					return ErrorCode("8001")
+				}
+				if game.IsFinal() {
+					return ErrorCode("8005")
+				}
+				AddEventOnce(game, event)
+				SortEvents(game)
+				return nil
+			})
+		}
+	default:
+		return item, errNotDeleted
+	}
+	var errorCode ErrorCode
+	if err != nil && !errors.As(err, &errorCode) {
+		logs.error1(ctx, "Unable to restore %s %s from the recycle bin: %v", itemType, code, err)
+	}
+	if err != nil {
+		return item, err
+	}
+
+	collection, key := deletedLocation(itemType, code)
+	store.datastore.Delete(ctx, collection, key)
+	return item, nil
+}
+
+// Permanently removes items that have been in the recycle bin for longer than the
+// retention period, returning how many were purged.
+func (store GameStore) purgeDeleted(ctx context.Context, now time.Time) int {
+	purged := store.purgeCollection(ctx, DELETED_COLLECTION, now)
+	for _, gameId := range store.datastore.Keys(ctx, DELETED_EVENTS_COLLECTION) {
+		purged += store.purgeCollection(ctx, deletedEventsCollection(gameId), now)
+		if len(store.datastore.Keys(ctx, deletedEventsCollection(gameId))) == 0 {
+			store.datastore.Delete(ctx, DELETED_EVENTS_COLLECTION, gameId)
+		}
+	}
+	return purged
+}
+
+func (store GameStore) purgeCollection(ctx context.Context, collection string, now time.Time) int {
+	purged := 0
+	for _, key := range store.datastore.Keys(ctx, collection) {
+		var item DeletedItem
+		store.datastore.Get(ctx, collection, key, &item)
+		if now.After(item.Expires()) {
+			store.datastore.Delete(ctx, collection, key)
+			purged++
+		}
+	}
+	return purged
+}
+
+// Runs the recycle bin sweeper in the background until the context is cancelled.
+func startSweeper(ctx context.Context, store GameStore, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				purged := store.purgeDeleted(ctx, now)
+				if purged > 0 {
+					logs.info("Purged %d expired items from the recycle bin", purged)
+				}
+			}
+		}
+	}()
+}
+
+// Returns the recycle bin entries for any deleted items in the user's history, along with
+// the events deleted from games in their history.
+func DeletedFromHistory(ctx context.Context, cookieValue string) []DeletedItem {
+	var items []DeletedItem
+	for _, id := range strings.Fields(cookieValue) {
+		itemType := "game"
+		parts := strings.Split(id, ":")
+		if len(parts) == 2 {
+			itemType = strings.ToLower(parts[0])
+			id = parts[1]
+		}
+
+		item := dataStore.getDeleted(ctx, itemType, id)
+		if item.ItemCode == id {
+			items = append(items, item)
+		}
+		if itemType == "game" {
+			items = append(items, dataStore.deletedEvents(ctx, id)...)
+		}
+	}
+	return items
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSoftDeleteAndRestoreGame(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	setupDataStore(store)
+	ctx := context.Background()
+
+	if !store.softDelete(ctx, "game", TEST_ID_1) {
+		t.Fatal("Game not deleted")
+	}
+	if store.datastore.Exists(ctx, GAMES_COLLECTION, TEST_ID_1) {
+		t.Error("Deleted game still in games collection")
+	}
+	item := store.getDeleted(ctx, "game", TEST_ID_1)
+	if item.ItemCode != TEST_ID_1 || item.Summary != testGame1().Title {
+		t.Errorf("Unexpected recycle bin entry: %+v", item)
+	}
+
+	if _, err := store.restore(ctx, "game", TEST_ID_1); err != nil {
+		t.Fatal("Game not restored")
+	}
+	game := store.getGame(ctx, TEST_ID_1)
+	if game.ID != TEST_ID_1 || len(game.Events) != len(testGame1().Events) {
+		t.Errorf("Restored game does not match original: %+v", game)
+	}
+	if store.datastore.Exists(ctx, DELETED_COLLECTION, deletedKey("game", TEST_ID_1)) {
+		t.Error("Restored game still in recycle bin")
+	}
+}
+
+func TestSoftDeleteMissingItem(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	ctx := context.Background()
+
+	if store.softDelete(ctx, "game", "NO-SUCH-GAME") {
+		t.Error("Missing game reported as deleted")
+	}
+	if store.softDelete(ctx, "widget", TEST_ID_1) {
+		t.Error("Unknown item type reported as deleted")
+	}
+}
+
+func TestRestoreEventToLockedGame(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	setupDataStore(store)
+	ctx := context.Background()
+
+	game := store.getGame(ctx, TEST_ID_1)
+	eventId := game.Events[0].ID
+	store.deleteEvent(ctx, &game, eventId)
+	game.LockedWith = "key"
+	store.putGame(ctx, game.ID, game)
+
+	if _, err := store.restore(ctx, "event", eventCode(TEST_ID_1, eventId)); err != ErrorCode("8001") {
+		t.Errorf("Expected locked game error, got %v", err)
+	}
+	if len(store.deletedEvents(ctx, TEST_ID_1)) != 1 {
+		t.Error("Event should stay in the recycle bin")
+	}
+}
+
+func TestDeleteAndRestoreEvent(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	setupDataStore(store)
+	ctx := context.Background()
+
+	game := store.getGame(ctx, TEST_ID_1)
+	eventCount := len(game.Events)
+	eventId := game.Events[0].ID
+
+	if _, ok := store.deleteEvent(ctx, &game, eventId); !ok {
+		t.Fatal("Event not deleted")
+	}
+	store.putGame(ctx, game.ID, game)
+
+	deleted := store.deletedEvents(ctx, TEST_ID_1)
+	if len(deleted) != 1 || deleted[0].GameID != TEST_ID_1 {
+		t.Fatalf("Unexpected deleted events: %+v", deleted)
+	}
+
+	store.restore(ctx, "event", deleted[0].ItemCode)
+	game = store.getGame(ctx, TEST_ID_1)
+	if len(game.Events) != eventCount {
+		t.Errorf("Expected %d events after restore, got %d", eventCount, len(game.Events))
+	}
+	if len(store.deletedEvents(ctx, TEST_ID_1)) != 0 {
+		t.Error("Restored event still in recycle bin")
+	}
+}
+
+func TestDeletedEventsOnlyForGame(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	setupDataStore(store)
+	ctx := context.Background()
+
+	for _, gameId := range []string{TEST_ID_1, TEST_ID_2} {
+		game := store.getGame(ctx, gameId)
+		store.deleteEvent(ctx, &game, game.Events[0].ID)
+		store.putGame(ctx, gameId, game)
+	}
+	store.softDelete(ctx, "list", TEST_LIST_ID)
+
+	deleted := store.deletedEvents(ctx, TEST_ID_1)
+	if len(deleted) != 1 || deleted[0].GameID != TEST_ID_1 {
+		t.Errorf("Unexpected deleted events: %+v", deleted)
+	}
+}
+
+func TestRestoreWithBadData(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	ctx := context.Background()
+	store.datastore.Put(ctx, DELETED_COLLECTION, deletedKey("team", "TEAM-9"), DeletedItem{ItemType: "team", ItemCode: "TEAM-9", Data: "{"})
+
+	if _, err := store.restore(ctx, "team", "TEAM-9"); err == nil {
+		t.Error("Item that can't be read was restored")
+	}
+	if store.getDeleted(ctx, "team", "TEAM-9").ItemCode != "TEAM-9" {
+		t.Error("Item that can't be read should stay in the recycle bin")
+	}
+}
+
+func TestPurgeDeletedEvents(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	setupDataStore(store)
+	ctx := context.Background()
+
+	game := store.getGame(ctx, TEST_ID_1)
+	store.deleteEvent(ctx, &game, game.Events[0].ID)
+	store.putGame(ctx, TEST_ID_1, game)
+
+	if store.purgeDeleted(ctx, time.Now().Add(retentionPeriod()+time.Hour)) != 1 {
+		t.Error("Expired event not purged")
+	}
+	if len(store.deletedEvents(ctx, TEST_ID_1)) != 0 || len(store.datastore.Keys(ctx, DELETED_EVENTS_COLLECTION)) != 0 {
+		t.Error("Purged event still in recycle bin")
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	store := GameStore{datastore: testDataStore()}
+	setupDataStore(store)
+	ctx := context.Background()
+
+	store.softDelete(ctx, "list", TEST_LIST_ID)
+
+	if store.purgeDeleted(ctx, time.Now()) != 0 {
+		t.Error("Item purged before retention period ended")
+	}
+	if store.purgeDeleted(ctx, time.Now().Add(retentionPeriod()+time.Hour)) != 1 {
+		t.Error("Expired item not purged")
+	}
+	if _, err := store.restore(ctx, "list", TEST_LIST_ID); err == nil {
+		t.Error("Purged item was restored")
+	}
+}
+
+func TestRetentionPeriod(t *testing.T) {
+	defer os.Unsetenv("DELETE_RETENTION_DAYS")
+
+	os.Setenv("DELETE_RETENTION_DAYS", "7")
+	if retentionPeriod() != 7*24*time.Hour {
+		t.Errorf("Unexpected retention period: %v", retentionPeriod())
+	}
+
+	os.Setenv("DELETE_RETENTION_DAYS", "never")
+	if retentionPeriod() != DEFAULT_RETENTION_DAYS*24*time.Hour {
+		t.Errorf("Invalid setting not ignored: %v", retentionPeriod())
+	}
+}
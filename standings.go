@@ -0,0 +1,185 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// The points awarded for each type of game result.
+type PointsRules struct {
+	Name         string
+	Win          int
+	OvertimeWin  int
+	OvertimeLoss int
+	Tie          int
+	Loss         int
+}
+
+var PointsSystems = []PointsRules{
+	{Name: "2-1-0", Win: 2, OvertimeWin: 2, OvertimeLoss: 1, Tie: 1, Loss: 0},
+	{Name: "3-2-1-0", Win: 3, OvertimeWin: 2, OvertimeLoss: 1, Tie: 1, Loss: 0},
+}
+
+const DEFAULT_POINTS_SYSTEM = "2-1-0"
+
+// Tie-breakers that can be applied, in order, to teams with equal points.
+var TieBreakers = map[string]string{
+	"rw":   "Regulation wins",
+	"wins": "Total wins",
+	"gd":   "Goal difference",
+	"gf":   "Goals for",
+	"ga":   "Fewest goals against",
+}
+
+var DefaultTieBreakers = []string{"wins", "gd", "gf"}
+
+type Standing struct {
+	Team           string
+	GamesPlayed    int
+	Wins           int
+	Losses         int
+	OvertimeWins   int
+	OvertimeLosses int
+	Ties           int
+	GoalsFor       int
+	GoalsAgainst   int
+	GoalDifference int
+	Points         int
+}
+
+// Returns the named points system, or the default if the name is not recognised.
+func GetPointsRules(name string) PointsRules {
+	for _, rules := range PointsSystems {
+		if rules.Name == name {
+			return rules
+		}
+	}
+	return GetPointsRules(DEFAULT_POINTS_SYSTEM)
+}
+
+// Removes any unrecognised tie-breakers, falling back to the defaults if none are left.
+func ValidTieBreakers(names []string) []string {
+	var valid []string
+	for _, name := range names {
+		if _, ok := TieBreakers[name]; ok {
+			valid = append(valid, name)
+		}
+	}
+	if len(valid) == 0 {
+		return append([]string(nil), DefaultTieBreakers...)
+	}
+	return valid
+}
+
+// Calculates the league table for a set of games. Only final games are counted, apart from
+// games recorded before statuses were introduced, which count once they have any events.
+func Standings(games []Game, rules PointsRules, tieBreakers []string) []Standing {
+	teams := make(map[string]*Standing)
+	var order []string
+
+	team := func(name string) *Standing {
+		key := strings.ToLower(strings.TrimSpace(name))
+		standing, ok := teams[key]
+		if !ok {
+			standing = &Standing{Team: strings.TrimSpace(name)}
+			teams[key] = standing
+			order = append(order, key)
+		}
+		return standing
+	}
+
+	for _, game := range games {
+		if !gamePlayed(game) {
+			continue
+		}
+		summary := summarise(game)
+		home := team(game.HomeTeam)
+		away := team(game.AwayTeam)
+		overtime := decidedInOvertime(summary)
+
+		addResult(home, summary.HomeGoals, summary.AwayGoals, overtime, rules)
+		addResult(away, summary.AwayGoals, summary.HomeGoals, overtime, rules)
+	}
+
+	standings := make([]Standing, 0, len(order))
+	for _, key := range order {
+		standings = append(standings, *teams[key])
+	}
+	SortStandings(standings, ValidTieBreakers(tieBreakers))
+	return standings
+}
+
+func gamePlayed(game Game) bool {
+	if game.Status != "" {
+		return game.IsFinal()
+	}
+	return len(game.Events) > 0
+}
+
+// Returns true if the scores were level at the end of regulation time.
+func decidedInOvertime(summary GameSummary) bool {
+	home, away := 0, 0
+	for n := 0; n < 3; n++ {
+		home += summary.Periods[n].HomeGoals
+		away += summary.Periods[n].AwayGoals
+	}
+	return home == away
+}
+
+func addResult(standing *Standing, goalsFor int, goalsAgainst int, overtime bool, rules PointsRules) {
+	standing.GamesPlayed++
+	standing.GoalsFor += goalsFor
+	standing.GoalsAgainst += goalsAgainst
+	standing.GoalDifference = standing.GoalsFor - standing.GoalsAgainst
+
+	switch {
+	case goalsFor == goalsAgainst:
+		standing.Ties++
+		standing.Points += rules.Tie
+	case goalsFor > goalsAgainst && overtime:
+		standing.OvertimeWins++
+		standing.Points += rules.OvertimeWin
+	case goalsFor > goalsAgainst:
+		standing.Wins++
+		standing.Points += rules.Win
+	case overtime:
+		standing.OvertimeLosses++
+		standing.Points += rules.OvertimeLoss
+	default:
+		standing.Losses++
+		standing.Points += rules.Loss
+	}
+}
+
+// Sorts standings by points, then by each of the tie-breakers in turn, then by team name.
+func SortStandings(standings []Standing, tieBreakers []string) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		for _, tieBreaker := range tieBreakers {
+			x, y := tieBreakerValue(a, tieBreaker), tieBreakerValue(b, tieBreaker)
+			if x != y {
+				return x > y
+			}
+		}
+		return a.Team < b.Team
+	})
+}
+
+func tieBreakerValue(standing Standing, tieBreaker string) int {
+	switch tieBreaker {
+	case "rw":
+		return standing.Wins
+	case "wins":
+		return standing.Wins + standing.OvertimeWins
+	case "gd":
+		return standing.GoalDifference
+	case "gf":
+		return standing.GoalsFor
+	case "ga":
+		return -standing.GoalsAgainst
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package main
+
+import "testing"
+
+func standingsGame(home string, away string, homeGoals []int, awayGoals []int) Game {
+	game := Game{HomeTeam: home, AwayTeam: away}
+	for period, goals := range homeGoals {
+		for n := 0; n < goals; n++ {
+			AddGoal(&game, period+1, "10:00", HOME, 10, 0, 0, "Even")
+		}
+	}
+	for period, goals := range awayGoals {
+		for n := 0; n < goals; n++ {
+			AddGoal(&game, period+1, "10:00", AWAY, 20, 0, 0, "Even")
+		}
+	}
+	game.Status = STATUS_FINAL
+	return game
+}
+
+func TestStandings(t *testing.T) {
+	games := []Game{
+		standingsGame("Reds", "Blues", []int{2, 1, 0}, []int{0, 0, 1}),
+		standingsGame("Blues", "Greens", []int{1, 0, 0, 1}, []int{0, 0, 1}),
+		standingsGame("Greens", "Reds", []int{0, 0, 0}, []int{1, 0, 0}),
+		{HomeTeam: "Reds", AwayTeam: "Greens"},
+		{HomeTeam: "Reds", AwayTeam: "Greens", Status: STATUS_IN_PROGRESS, Events: []Event{{EventType: GOAL, Period: 1, HomeAway: HOME}}},
+	}
+
+	standings := Standings(games, GetPointsRules("3-2-1-0"), nil)
+
+	if len(standings) != 3 {
+		t.Fatalf("Unexpected number of teams: %d", len(standings))
+	}
+
+	reds := standings[0]
+	if reds.Team != "Reds" || reds.GamesPlayed != 2 || reds.Wins != 2 || reds.Points != 6 {
+		t.Errorf("Unexpected leader: %+v", reds)
+	}
+	if reds.GoalDifference != 3 {
+		t.Errorf("Unexpected goal difference: %d", reds.GoalDifference)
+	}
+
+	blues := standings[1]
+	if blues.Team != "Blues" || blues.OvertimeWins != 1 || blues.Points != 2 {
+		t.Errorf("Unexpected second place: %+v", blues)
+	}
+
+	greens := standings[2]
+	if greens.OvertimeLosses != 1 || greens.Losses != 1 || greens.Points != 1 {
+		t.Errorf("Unexpected third place: %+v", greens)
+	}
+}
+
+func TestStandingsTieBreakers(t *testing.T) {
+	standings := []Standing{
+		{Team: "A", Points: 4, Wins: 1, OvertimeWins: 1, GoalsFor: 10, GoalsAgainst: 5, GoalDifference: 5},
+		{Team: "B", Points: 4, Wins: 2, GoalsFor: 6, GoalsAgainst: 5, GoalDifference: 1},
+	}
+
+	SortStandings(standings, []string{"gd"})
+	if standings[0].Team != "A" {
+		t.Errorf("Goal difference tie-breaker not applied: %v", standings)
+	}
+
+	SortStandings(standings, []string{"rw"})
+	if standings[0].Team != "B" {
+		t.Errorf("Regulation wins tie-breaker not applied: %v", standings)
+	}
+
+	if len(ValidTieBreakers([]string{"bogus"})) != len(DefaultTieBreakers) {
+		t.Error("Invalid tie-breakers were not replaced by the defaults")
+	}
+	ValidTieBreakers(nil)[0] = "gf"
+	if DefaultTieBreakers[0] != "wins" {
+		t.Error("Changing the returned tie-breakers should not change the defaults")
+	}
+}
+
+func TestGetPointsRules(t *testing.T) {
+	if GetPointsRules("3-2-1-0").Win != 3 {
+		t.Error("Unexpected points for a win in 3-2-1-0 system")
+	}
+	if GetPointsRules("").Name != DEFAULT_POINTS_SYSTEM {
+		t.Error("Default points system not used")
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Statistics for one player, totalled across all the games in a list.
+type SeasonPlayerStats struct {
+	Key              string
+	Team             string
+	Number           int
+	Name             string
+	GamesPlayed      int
+	Goals            int
+	Assists          int
+	Points           int
+	Minutes          int
+	PowerPlayGoals   int
+	ShortHandedGoals int
+	PlusMinus        int
+	Faceoffs         FaceoffStats
+}
+
+type TeamStats struct {
+	Team    string
+	Players []SeasonPlayerStats
+}
+
+var StatsSortColumns = []string{"pts", "g", "a", "pim", "ppg", "shg", "pm", "fow", "gp", "num"}
+
+// Totals the player statistics across a set of games, grouped by team. Players are matched
+// by their team registry ID where the game roster came from the registry, otherwise by
+// team name and number.
+func SeasonStatistics(games []Game) []TeamStats {
+	players := make(map[string]*SeasonPlayerStats)
+
+	for _, game := range games {
+		summary := summarise(game)
+		addGameStats(players, game.HomeTeam, game.HomeRoster, summary.HomePlayers)
+		addGameStats(players, game.AwayTeam, game.AwayRoster, summary.AwayPlayers)
+	}
+
+	teams := make(map[string]*TeamStats)
+	var teamNames []string
+	for _, player := range players {
+		team, ok := teams[player.Team]
+		if !ok {
+			team = &TeamStats{Team: player.Team}
+			teams[player.Team] = team
+			teamNames = append(teamNames, player.Team)
+		}
+		team.Players = append(team.Players, *player)
+	}
+
+	sort.Strings(teamNames)
+	stats := make([]TeamStats, 0, len(teamNames))
+	for _, name := range teamNames {
+		SortStats(teams[name].Players, "pts")
+		stats = append(stats, *teams[name])
+	}
+	return stats
+}
+
+func addGameStats(players map[string]*SeasonPlayerStats, team string, roster []Player, summaries map[int]PlayerSummary) {
+	played := make(map[int]bool)
+	for _, player := range roster {
+		if !player.Scratched {
+			played[player.Number] = true
+		}
+	}
+	for number := range summaries {
+		// Bench penalties and unknown scorers are recorded against #0, which isn't a player
+		if number != 0 {
+			played[number] = true
+		}
+	}
+
+	for number := range played {
+		key := playerStatsKey(team, roster, number)
+		stats, ok := players[key]
+		if !ok {
+			stats = &SeasonPlayerStats{Key: key, Team: team, Number: number}
+			players[key] = stats
+		}
+
+		if player := FindPlayer(roster, number); player != nil {
+			stats.Number = number
+			if player.Name != "" {
+				stats.Name = player.Name
+			}
+		}
+
+		summary := summaries[number]
+		stats.GamesPlayed++
+		stats.Goals += summary.Goals
+		stats.Assists += summary.Assists
+		stats.Points += summary.Goals + summary.Assists
+		stats.Minutes += summary.Minutes
+		stats.PowerPlayGoals += summary.PowerPlayGoals
+		stats.ShortHandedGoals += summary.ShortHandedGoals
+		stats.PlusMinus += summary.PlusMinus
+		stats.Faceoffs.Won += summary.Faceoffs.Won
+		stats.Faceoffs.Lost += summary.Faceoffs.Lost
+	}
+}
+
+func playerStatsKey(team string, roster []Player, number int) string {
+	player := FindPlayer(roster, number)
+	if player != nil && player.ID != "" {
+		return player.ID
+	}
+	return strings.ToLower(strings.TrimSpace(team)) + "#" + strconv.Itoa(number)
+}
+
+// Sorts player statistics into a leaderboard by the named column, highest first.
+// Ties are broken by points then goals, and finally by player number.
+func SortStats(players []SeasonPlayerStats, column string) {
+	sort.SliceStable(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		if column == "num" {
+			return a.Number < b.Number
+		}
+		if x, y := statsColumn(a, column), statsColumn(b, column); x != y {
+			return x > y
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Goals != b.Goals {
+			return a.Goals > b.Goals
+		}
+		return a.Number < b.Number
+	})
+}
+
+func statsColumn(stats SeasonPlayerStats, column string) int {
+	switch column {
+	case "g":
+		return stats.Goals
+	case "a":
+		return stats.Assists
+	case "pim":
+		return stats.Minutes
+	case "ppg":
+		return stats.PowerPlayGoals
+	case "shg":
+		return stats.ShortHandedGoals
+	case "pm":
+		return stats.PlusMinus
+	case "fow":
+		return stats.Faceoffs.Won
+	case "gp":
+		return stats.GamesPlayed
+	default:
+		return stats.Points
+	}
+}
+
+func validStatsColumn(column string) string {
+	for _, valid := range StatsSortColumns {
+		if column == valid {
+			return column
+		}
+	}
+	return "pts"
+}
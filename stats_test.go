@@ -0,0 +1,85 @@
+package main
+
+import "testing"
+
+func TestSeasonStatistics(t *testing.T) {
+	game1 := testGame1()
+	game2 := testGame1()
+	game2.HomeRoster = append([]Player{}, game1.HomeRoster...)
+	AddGoal(&game2, 2, "10:00", HOME, 89, 41, 0, "PP")
+	game2.HomeRoster[0].Scratched = true
+
+	stats := SeasonStatistics([]Game{game1, game2})
+
+	if len(stats) != 2 {
+		t.Fatalf("Unexpected number of teams: %d", len(stats))
+	}
+	if stats[1].Team != "Reds" {
+		t.Fatalf("Teams not sorted by name: %s", stats[1].Team)
+	}
+
+	reds := stats[1].Players
+	if reds[0].Number != 41 || reds[0].Points != 3 || reds[0].GamesPlayed != 2 {
+		t.Errorf("Unexpected leader: %+v", reds[0])
+	}
+	if reds[0].Name != "Smith, J" {
+		t.Errorf("Player name not taken from roster: %s", reds[0].Name)
+	}
+
+	for _, player := range reds {
+		if player.Number == 89 && player.PowerPlayGoals != 1 {
+			t.Errorf("Unexpected PPG for #89: %d", player.PowerPlayGoals)
+		}
+		if player.Number == 1 && player.GamesPlayed != 1 {
+			t.Errorf("Scratched goalie counted as playing: %d", player.GamesPlayed)
+		}
+	}
+}
+
+func TestSeasonStatisticsSkipsBench(t *testing.T) {
+	game := testGame1()
+	AddPenalty(&game, 2, "10:00", HOME, 0, 2, "Too Many Men")
+
+	for _, team := range SeasonStatistics([]Game{game}) {
+		for _, player := range team.Players {
+			if player.Number == 0 {
+				t.Errorf("Bench penalty counted as a player for %s: %+v", team.Team, player)
+			}
+		}
+	}
+}
+
+func TestSeasonStatisticsByRegistryId(t *testing.T) {
+	game1 := testGame1()
+	game2 := testGame1()
+	game2.HomeRoster = game1.HomeRoster
+	game2.HomeTeam = "Reds II"
+
+	stats := SeasonStatistics([]Game{game1, game2})
+
+	if len(stats) != 2 {
+		t.Errorf("Registry players were not matched across team names: %d teams", len(stats))
+	}
+}
+
+func TestSortStats(t *testing.T) {
+	players := []SeasonPlayerStats{
+		{Number: 10, Goals: 1, Points: 3, Minutes: 2},
+		{Number: 20, Goals: 2, Points: 2, Minutes: 10},
+		{Number: 5, Goals: 1, Points: 3},
+	}
+
+	SortStats(players, "pts")
+	if players[0].Number != 5 || players[1].Number != 10 {
+		t.Errorf("Unexpected points order: %v", players)
+	}
+
+	SortStats(players, "pim")
+	if players[0].Number != 20 {
+		t.Errorf("Unexpected PIM order: %v", players)
+	}
+
+	if validStatsColumn("bogus") != "pts" {
+		t.Error("Invalid sort column was not replaced by the default")
+	}
+}
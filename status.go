@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const STATUS_SCHEDULED = "Scheduled"
+const STATUS_IN_PROGRESS = "In progress"
+const STATUS_INTERMISSION = "Intermission"
+const STATUS_FINAL = "Final"
+
+const PERIOD_START = "Period Start"
+const PERIOD_END = "Period End"
+
+// Returns the status of the game. Games recorded before statuses were introduced are
+// treated as in progress once they have any events.
+func (game Game) CurrentStatus() string {
+	if game.Status != "" {
+		return game.Status
+	}
+	if len(game.Events) > 0 {
+		return STATUS_IN_PROGRESS
+	}
+	return STATUS_SCHEDULED
+}
+
+func (game Game) IsFinal() bool {
+	return game.Status == STATUS_FINAL
+}
+
+// Moves the game to the status implied by a newly added event.
+func UpdateStatus(game *Game, event Event) {
+	if game.IsFinal() {
+		return
+	}
+	switch event.EventType {
+	case PERIOD_START:
+		game.Status = STATUS_IN_PROGRESS
+	case PERIOD_END:
+		game.Status = STATUS_INTERMISSION
+	default:
+		if game.CurrentStatus() == STATUS_SCHEDULED {
+			game.Status = STATUS_IN_PROGRESS
+		}
+	}
+}
+
+// Returns the highest period that has been ended with a period end event.
+func LastPeriodEnded(game Game) int {
+	period := 0
+	for _, event := range game.Events {
+		if event.EventType == PERIOD_END && event.Period > period {
+			period = event.Period
+		}
+	}
+	return period
+}
+
+// Records the start or end of a period. Periods start at 20:00 on the clock and end at 00:00.
+func AddPeriodEvent(game *Game, eventType string, period int) {
+	clockTime := EventTime("20:00")
+	if eventType == PERIOD_END {
+		clockTime = "00:00"
+	}
+	event := Event{
+		ID:        randomEventId(),
+		Period:    period,
+		ClockTime: clockTime,
+		GameTime:  ClockToGameTime(period, clockTime),
+		EventType: eventType,
+	}
+	AddEvent(game, event)
+}
+
+// Checks that the scoresheet is complete and consistent, returning a description of
+// each problem found.
+func ValidateForFinal(game Game) []string {
+	var problems []string
+
+	if game.CurrentStatus() == STATUS_SCHEDULED {
+		problems = append(problems, "No events have been recorded")
+	}
+
+	starts, ends := 0, 0
+	for _, event := range game.Events {
+		when := fmt.Sprintf("P%d %s", event.Period, event.ClockTime)
+		if !ValidPeriod(event.Period) {
+			problems = append(problems, fmt.Sprintf("%s %s %s is not in a valid period", event.HomeAway, event.EventType, event.ClockTime))
+		}
+		switch event.EventType {
+		case PERIOD_START:
+			starts++
+		case PERIOD_END:
+			ends++
+		case GOAL:
+			if event.Player == 0 {
+				problems = append(problems, fmt.Sprintf("%s goal at %s has no scorer", event.HomeAway, when))
+			} else if event.Assist1 == event.Player || event.Assist2 == event.Player {
+				problems = append(problems, fmt.Sprintf("%s goal at %s is assisted by the scorer", event.HomeAway, when))
+			}
+			if event.Assist2 > 0 && event.Assist1 == event.Assist2 {
+				problems = append(problems, fmt.Sprintf("%s goal at %s has the same player for both assists", event.HomeAway, when))
+			}
+		case PENALTY_SHOT:
+			problems = append(problems, validatePenaltyShot(game, event, when)...)
+		case PENALTY:
+			if event.Player == 0 || event.Minutes == 0 {
+				problems = append(problems, fmt.Sprintf("%s penalty at %s is unfinished, with no player or minutes", event.HomeAway, when))
+			}
+		}
+	}
+
+	if starts > ends {
+		problems = append(problems, "The current period has not been ended")
+	}
+	for _, homeAway := range []string{HOME, AWAY} {
+		if countTimeouts(game, homeAway) > TIMEOUTS_PER_GAME {
+			problems = append(problems, fmt.Sprintf("%s team has more timeouts recorded than the %d allowed", homeAway, TIMEOUTS_PER_GAME))
+		}
+	}
+	if game.Clock.Running {
+		problems = append(problems, "The game clock is still running")
+	}
+
+	problems = append(problems, checkOvertimeScore(summarise(game))...)
+
+	return problems
+}
+
+// Overtime is sudden death, so there should only be an overtime goal if the scores were
+// level after regulation time, and there can only be one.
+func checkOvertimeScore(summary GameSummary) []string {
+	ot := summary.Periods[3]
+	if ot.HomeGoals+ot.AwayGoals == 0 {
+		return nil
+	}
+
+	var problems []string
+	if !decidedInOvertime(summary) {
+		problems = append(problems, "Overtime goal recorded when the scores were not level after regulation time")
+	}
+	if ot.HomeGoals+ot.AwayGoals > 1 {
+		problems = append(problems, "More than one overtime goal recorded")
+	}
+	return problems
+}
+
+// Marks the game as final, if it passes validation, optionally locking it with the given key.
+func Finalise(game *Game, lockKey string) []string {
+	problems := ValidateForFinal(*game)
+	if len(problems) > 0 {
+		return problems
+	}
+
+	game.Status = STATUS_FINAL
+
+	lockKey = strings.TrimSpace(lockKey)
+	if lockKey != "" {
+		game.LockedWith = lockKey
+	}
+
+	return nil
+}
+
+// Reopens a final game for further editing, unless it has been signed off.
+func Reopen(game *Game) {
+	if game.IsFinal() && !game.SignedOff() {
+		game.Status = STATUS_INTERMISSION
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatusLifecycle(t *testing.T) {
+	var game Game
+
+	if game.CurrentStatus() != STATUS_SCHEDULED {
+		t.Errorf("Unexpected initial status: %s", game.CurrentStatus())
+	}
+
+	AddPeriodEvent(&game, PERIOD_START, 1)
+	if game.CurrentStatus() != STATUS_IN_PROGRESS {
+		t.Errorf("Unexpected status after period start: %s", game.CurrentStatus())
+	}
+
+	AddGoal(&game, 1, "10:00", HOME, 9, 0, 0, "Even")
+	AddPeriodEvent(&game, PERIOD_END, 1)
+	if game.CurrentStatus() != STATUS_INTERMISSION {
+		t.Errorf("Unexpected status after period end: %s", game.CurrentStatus())
+	}
+	if LastPeriodEnded(game) != 1 {
+		t.Errorf("Unexpected last period ended: %d", LastPeriodEnded(game))
+	}
+	if game.Events[2].GameTime != "20:00" {
+		t.Errorf("Unexpected period end game time: %s", game.Events[2].GameTime)
+	}
+
+	problems := Finalise(&game, "")
+	if len(problems) > 0 || !game.IsFinal() {
+		t.Errorf("Game could not be finalised: %v", problems)
+	}
+
+	AddPeriodEvent(&game, PERIOD_START, 2)
+	if !game.IsFinal() {
+		t.Error("Adding events should not change the status of a final game")
+	}
+
+	Reopen(&game)
+	if game.CurrentStatus() != STATUS_INTERMISSION {
+		t.Errorf("Unexpected status after reopening: %s", game.CurrentStatus())
+	}
+}
+
+func TestValidateForFinal(t *testing.T) {
+	var game Game
+	AddPeriodEvent(&game, PERIOD_START, 1)
+	AddGoal(&game, 1, "10:00", HOME, 9, 9, 0, "Even")
+	AddPenalty(&game, 1, "09:00", AWAY, 0, 2, "Trip")
+	AddGoal(&game, 4, "03:00", HOME, 10, 0, 0, "Even")
+	game.Clock.Running = true
+
+	problems := ValidateForFinal(game)
+
+	if len(problems) != 5 {
+		t.Errorf("Unexpected problems: %v", problems)
+	}
+	if Finalise(&game, "key") == nil || game.IsFinal() || game.LockedWith != "" {
+		t.Error("Invalid game should not be finalised or locked")
+	}
+}
+
+func TestValidateEventsOutsidePeriods(t *testing.T) {
+	var game Game
+	AddPeriodEvent(&game, PERIOD_END, 0)
+	AddGoal(&game, 5, "10:00", HOME, 9, 0, 0, "Even")
+
+	problems := ValidateForFinal(game)
+
+	if len(problems) < 2 || !strings.Contains(strings.Join(problems, "\n"), "is not in a valid period") {
+		t.Errorf("Events outside the periods should be reported: %v", problems)
+	}
+	if summary := summarise(game); summary.HomeGoals != 0 {
+		t.Errorf("Goal outside the periods should not be counted: %+v", summary)
+	}
+}
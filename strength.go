@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+)
+
+// A PenaltyComponent is an extra part of a multi-part penalty, e.g. the game misconduct
+// given with a fighting major.
+type PenaltyComponent struct {
+	Type    string
+	Minutes int
+}
+
+// The minutes a penalty leaves the team short-handed for. Misconducts are served without
+// affecting the strength of the teams.
+var PenaltyStrengthMinutes = map[string]int{
+	PENALTY_MINOR:        2,
+	PENALTY_BENCH_MINOR:  2,
+	PENALTY_DOUBLE_MINOR: 4,
+	PENALTY_MAJOR:        5,
+	PENALTY_MATCH:        5,
+}
+
+// Returns the types of all the parts of a penalty, starting with the main one.
+func PenaltyParts(event Event) []string {
+	parts := []string{PenaltyType(event)}
+	for _, component := range event.Components {
+		parts = append(parts, component.Type)
+	}
+	return parts
+}
+
+// Describes the parts of a multi-part penalty, e.g. "Major + Game Misconduct".
+func (event Event) PenaltyDescription() string {
+	return strings.Join(PenaltyParts(event), " + ")
+}
+
+// Returns the minutes a penalty leaves the team short-handed for, which is the longest of
+// any of its parts, e.g. 5 for a minor given with a match penalty.
+func StrengthMinutes(event Event) int {
+	minutes := 0
+	for _, part := range PenaltyParts(event) {
+		minutes = max(minutes, PenaltyStrengthMinutes[part])
+	}
+	return minutes
+}
+
+// Works out which penalties are coincidental, so do not create a power play. Penalties can
+// be marked as coincidental when they are recorded, otherwise penalties given to both teams
+// at the same time cancel out when they would leave each team short for the same time.
+// Returns the IDs of the coincidental penalties.
+func CoincidentalPenalties(game Game) map[string]bool {
+	coincidental := make(map[string]bool)
+	byTime := make(map[EventTime][]Event)
+	for _, event := range game.Events {
+		if event.EventType != PENALTY {
+			continue
+		}
+		if event.Coincidental {
+			coincidental[event.ID] = true
+		} else if StrengthMinutes(event) > 0 {
+			byTime[event.GameTime] = append(byTime[event.GameTime], event)
+		}
+	}
+
+	for _, penalties := range byTime {
+		for _, home := range penalties {
+			if home.HomeAway != HOME {
+				continue
+			}
+			for _, away := range penalties {
+				if away.HomeAway == AWAY && !coincidental[away.ID] && StrengthMinutes(away) == StrengthMinutes(home) {
+					coincidental[home.ID] = true
+					coincidental[away.ID] = true
+					break
+				}
+			}
+		}
+	}
+	return coincidental
+}
+
+// Returns true if the penalty gives the other team a power play.
+func createsPowerPlay(event Event, coincidental map[string]bool) bool {
+	return event.EventType == PENALTY && StrengthMinutes(event) > 0 && !coincidental[event.ID]
+}
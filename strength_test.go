@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+)
+
+func addCataloguePenalty(game *Game, period int, clockTime EventTime, homeAway string, player int, code string, components ...string) Event {
+	event := Event{
+		ID:        randomEventId(),
+		Period:    period,
+		ClockTime: clockTime,
+		GameTime:  ClockToGameTime(period, clockTime),
+		EventType: PENALTY,
+		HomeAway:  homeAway,
+		Player:    player,
+		Category:  code,
+	}
+	for _, component := range components {
+		event.Components = append(event.Components, PenaltyComponent{Type: component})
+	}
+	CatalogueFor(game.Competition).Validate(&event)
+	AddEvent(game, event)
+	return event
+}
+
+func TestMultiPartPenalty(t *testing.T) {
+	var game Game
+	fight := addCataloguePenalty(&game, 1, "10:00", HOME, 41, "FIGHT", PENALTY_GAME_MISCONDUCT)
+
+	if fight.Minutes != 25 || fight.PenaltyDescription() != "Major + Game Misconduct" {
+		t.Errorf("Unexpected multi-part penalty: %d minutes, %s", fight.Minutes, fight.PenaltyDescription())
+	}
+	if StrengthMinutes(fight) != 5 {
+		t.Errorf("Expected 5 minutes short-handed, got %d", StrengthMinutes(fight))
+	}
+
+	summary := summarise(game)
+	if summary.HomePIM != 25 || summary.HomePlayers[41].Minutes != 25 || summary.AwayPowerPlays != 1 {
+		t.Errorf("Unexpected summary: PIM %d, player %d, power plays %d", summary.HomePIM, summary.HomePlayers[41].Minutes, summary.AwayPowerPlays)
+	}
+
+	records := Discipline([]Game{game}, DefaultDisciplineRules)
+	if records[0].GamesSuspended != DefaultDisciplineRules.GameMisconductGames {
+		t.Errorf("Game misconduct part not used for discipline: %+v", records[0])
+	}
+}
+
+func TestStrengthMinutesUsesLongestPart(t *testing.T) {
+	minorAndMatch := Event{EventType: PENALTY, PenaltyType: PENALTY_MINOR, Minutes: 27, Components: []PenaltyComponent{{Type: PENALTY_MATCH}}}
+	if StrengthMinutes(minorAndMatch) != 5 {
+		t.Errorf("Expected 5 minutes short-handed for a minor with a match penalty, got %d", StrengthMinutes(minorAndMatch))
+	}
+
+	majorAndMinor := Event{EventType: PENALTY, PenaltyType: PENALTY_MAJOR, Minutes: 7, Components: []PenaltyComponent{{Type: PENALTY_MINOR}}}
+	if StrengthMinutes(majorAndMinor) != 5 {
+		t.Errorf("Expected 5 minutes short-handed for a major with a minor, got %d", StrengthMinutes(majorAndMinor))
+	}
+}
+
+func TestCoincidentalMinors(t *testing.T) {
+	var game Game
+	home := addCataloguePenalty(&game, 1, "10:00", HOME, 41, "ROUGH")
+	away := addCataloguePenalty(&game, 1, "10:00", AWAY, 7, "ROUGH")
+	trip := addCataloguePenalty(&game, 1, "08:00", AWAY, 8, "TRIP")
+
+	coincidental := CoincidentalPenalties(game)
+	if !coincidental[home.ID] || !coincidental[away.ID] || coincidental[trip.ID] {
+		t.Errorf("Unexpected coincidental penalties: %v", coincidental)
+	}
+
+	summary := summarise(game)
+	if summary.HomePowerPlays != 1 || summary.AwayPowerPlays != 0 {
+		t.Errorf("Unexpected power plays: home %d, away %d", summary.HomePowerPlays, summary.AwayPowerPlays)
+	}
+	if summary.HomePIM != 2 || summary.AwayPIM != 4 {
+		t.Errorf("Unexpected PIM: home %d, away %d", summary.HomePIM, summary.AwayPIM)
+	}
+}
+
+func TestUnequalPenaltiesAreNotCoincidental(t *testing.T) {
+	var game Game
+	addCataloguePenalty(&game, 1, "10:00", HOME, 41, "ROUGH")
+	addCataloguePenalty(&game, 1, "10:00", AWAY, 7, "FIGHT")
+
+	if len(CoincidentalPenalties(game)) != 0 {
+		t.Error("Minor and major should not be coincidental")
+	}
+}
+
+func TestMarkedCoincidental(t *testing.T) {
+	var game Game
+	AddEvent(&game, Event{ID: "P1", Period: 1, GameTime: "10:00", EventType: PENALTY, HomeAway: HOME, Minutes: 2, Coincidental: true})
+
+	if summarise(game).AwayPowerPlays != 0 {
+		t.Error("Penalty marked as coincidental created a power play")
+	}
+}
+
+func TestMisconductDoesNotCreatePowerPlay(t *testing.T) {
+	var game Game
+	addCataloguePenalty(&game, 1, "10:00", HOME, 41, "ABUSE")
+
+	if summarise(game).AwayPowerPlays != 0 {
+		t.Error("Misconduct created a power play")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// A Team is a reusable roster that can be copied into new games.
+type Team struct {
+	ID         string
+	Name       string
+	Players    []Player
+	LockedWith string
+	Created    time.Time
+}
+
+type Player struct {
+	ID             string
+	Number         int
+	Name           string
+	Position       string
+	Shoots         string
+	Role           string
+	StartingGoalie bool
+	Scratched      bool
+}
+
+const CAPTAIN = "C"
+const ALTERNATE = "A"
+
+var Positions = []string{"C", "LW", "RW", "D", "G"}
+
+func NewTeam(name string) Team {
+	var team Team
+	team.Name = strings.TrimSpace(name)
+	team.Players = make([]Player, 0)
+	team.Created = time.Now()
+	return team
+}
+
+func (team Team) LinkCode() string {
+	return "TEAM:" + team.ID
+}
+
+func (team *Team) SetLockedWith(key string) {
+	team.LockedWith = key
+}
+
+func (team Team) IsLocked() bool {
+	return team.LockedWith != ""
+}
+
+func randomPlayerId() string {
+	return fmt.Sprintf("P%07X", rand.Intn(0xFFFFFFF))
+}
+
+// Adds a player to the team roster, replacing any existing player with the same number.
+// A player that replaces an existing one keeps the existing registry ID.
+func (team *Team) AddPlayer(player Player) {
+	player.Name = strings.TrimSpace(player.Name)
+
+	for n, existing := range team.Players {
+		if existing.Number == player.Number {
+			if player.ID == "" {
+				player.ID = existing.ID
+			}
+			team.Players[n] = player
+			return
+		}
+	}
+
+	if player.ID == "" {
+		player.ID = randomPlayerId()
+	}
+	team.Players = append(team.Players, player)
+	team.SortPlayers()
+}
+
+func (team *Team) RemovePlayer(number int) {
+	players := make([]Player, 0, len(team.Players))
+	for _, player := range team.Players {
+		if player.Number != number {
+			players = append(players, player)
+		}
+	}
+	team.Players = players
+}
+
+func (team *Team) SortPlayers() {
+	sortPlayers(team.Players)
+}
+
+func (player Player) Status() string {
+	if player.Scratched {
+		return "Scratched"
+	}
+	return "Dressed"
+}
+
+// Copies the team name and roster into one side of a game. The game keeps its own copy
+// so that later changes to the team do not alter the record of games already played.
+func CopyRoster(game *Game, homeAway string, team Team) {
+	if homeAway == HOME {
+		game.HomeTeamID = team.ID
+		if game.HomeTeam == "" {
+			game.HomeTeam = team.Name
+		}
+	} else if homeAway == AWAY {
+		game.AwayTeamID = team.ID
+		if game.AwayTeam == "" {
+			game.AwayTeam = team.Name
+		}
+	} else {
+		return
+	}
+
+	for _, player := range team.Players {
+		AddPlayer(game, homeAway, player)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Add handlers for maintaining the registry of teams and their rosters
+func AddTeamHandlers(e *echo.Echo) {
+	e.GET("/teams", codeRedirect)
+	e.GET("/team/:id", teamPage)
+	e.GET("/newTeam", newTeamPage)
+	e.POST("/addTeam", addTeamPost)
+	e.GET("/addTeamPlayer", addTeamPlayerPage)
+	e.POST("/addTeamPlayer", addTeamPlayerPost)
+	e.POST("/removeTeamPlayer", removeTeamPlayerPost)
+}
+
+func teamPage(c echo.Context) error {
+	teamId := c.Param("id")
+
+	ctx := gctx(c)
+	logs.info1(ctx, "GET for team ID: %s", teamId)
+
+	team := dataStore.getTeam(ctx, teamId)
+
+	if team.ID != teamId {
+		return showMissingItem("team", teamId, c)
+	}
+
+	var data pageData
+	data.Detail = team
+	data.PageHeading = team.Name
+
+	errorCode := c.QueryParam("e")
+	if errorCode != "" {
+		data.Error = errorMessage(errorCode)
+	}
+
+	setGameHistoryCookie(team.LinkCode(), c)
+
+	return c.Render(http.StatusOK, "team", data)
+}
+
+func newTeamPage(c echo.Context) error {
+	return c.Render(http.StatusOK, "newteam", nil)
+}
+
+func addTeamPost(c echo.Context) error {
+	ctx := gctx(c)
+
+	team := NewTeam(c.FormValue("team_name"))
+	id := dataStore.addTeam(ctx, team)
+
+	return c.Redirect(http.StatusSeeOther, "/team/"+id)
+}
+
+func addTeamPlayerPage(c echo.Context) error {
+	var data pageData
+	data.ItemType = "team"
+	data.ItemCode = c.QueryParam("team")
+
+	return c.Render(http.StatusOK, "newteamplayer", data)
+}
+
+func addTeamPlayerPost(c echo.Context) error {
+	teamId := strings.ToUpper(c.FormValue("team_id"))
+
+	ctx := gctx(c)
+
+	team := dataStore.getTeam(ctx, teamId)
+
+	if team.ID != teamId {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Team not found when adding player: %s", teamId))
+	}
+	if team.IsLocked() {
+		return c.Redirect(http.StatusSeeOther, "/team/"+teamId+"?e=8003")
+	}
+
+	playerNum, err := strconv.Atoi(c.FormValue("player_number"))
+	if err != nil {
+		return c.Redirect(http.StatusSeeOther, "/team/"+teamId+"?e=8002")
+	}
+
+	team.AddPlayer(Player{
+		Number:   playerNum,
+		Name:     c.FormValue("player_name"),
+		Position: c.FormValue("position"),
+		Shoots:   c.FormValue("shoots"),
+		Role:     strings.ToUpper(c.FormValue("role")),
+	})
+
+	dataStore.putTeam(ctx, teamId, team)
+
+	return c.Redirect(http.StatusSeeOther, "/team/"+teamId)
+}
+
+func removeTeamPlayerPost(c echo.Context) error {
+	teamId := strings.ToUpper(c.FormValue("team_id"))
+
+	ctx := gctx(c)
+
+	team := dataStore.getTeam(ctx, teamId)
+
+	if team.ID != teamId {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Team not found when removing player: %s", teamId))
+	}
+	if team.IsLocked() {
+		return c.Redirect(http.StatusSeeOther, "/team/"+teamId+"?e=8003")
+	}
+
+	playerNum, err := strconv.Atoi(c.FormValue("player_number"))
+	if err != nil {
+		return c.Redirect(http.StatusSeeOther, "/team/"+teamId+"?e=8002")
+	}
+
+	team.RemovePlayer(playerNum)
+
+	dataStore.putTeam(ctx, teamId, team)
+
+	return c.Redirect(http.StatusSeeOther, "/team/"+teamId)
+}
@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func TestTeamAddPlayer(t *testing.T) {
+	team := NewTeam(" Reds ")
+
+	team.AddPlayer(Player{Number: 41, Name: "Smith, J"})
+	team.AddPlayer(Player{Number: 7, Name: "Jones, A"})
+
+	if team.Name != "Reds" {
+		t.Errorf("Team name was not trimmed: `%s`", team.Name)
+	}
+	if len(team.Players) != 2 {
+		t.Errorf("Unexpected number of players: %d", len(team.Players))
+	}
+	if team.Players[0].Number != 7 {
+		t.Errorf("Players not sorted by number, first is %d", team.Players[0].Number)
+	}
+
+	id := team.Players[1].ID
+	team.AddPlayer(Player{Number: 41, Name: "Smith, John", Role: CAPTAIN})
+
+	if len(team.Players) != 2 {
+		t.Errorf("Replacing a player changed the roster size: %d", len(team.Players))
+	}
+	if team.Players[1].ID != id {
+		t.Errorf("Replacing a player changed the registry ID from %s to %s", id, team.Players[1].ID)
+	}
+	if team.Players[1].Name != "Smith, John" {
+		t.Errorf("Player was not replaced: %s", team.Players[1].Name)
+	}
+}
+
+func TestTeamRemovePlayer(t *testing.T) {
+	team := testTeam1()
+	count := len(team.Players)
+
+	team.RemovePlayer(41)
+
+	if len(team.Players) != count-1 {
+		t.Errorf("Unexpected number of players after removal: %d", len(team.Players))
+	}
+}
+
+func TestCopyRoster(t *testing.T) {
+	team := testTeam1()
+	game := Game{AwayTeam: "Blues"}
+
+	CopyRoster(&game, HOME, team)
+
+	if game.HomeTeam != "Reds" {
+		t.Errorf("Home team name not copied: %s", game.HomeTeam)
+	}
+	if game.HomeTeamID != TEST_TEAM_ID {
+		t.Errorf("Home team ID not recorded: %s", game.HomeTeamID)
+	}
+	player := FindPlayer(game.HomeRoster, 41)
+	if player == nil || player.Name != "Smith, J" || player.Role != CAPTAIN {
+		t.Errorf("Home player 41 not copied: %v", game.HomeRoster)
+	}
+
+	team.Players[0].Name = "Changed"
+	if FindPlayer(game.HomeRoster, 1).Name != "Green, T" {
+		t.Error("Changing the team altered the game roster")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"sort"
+)
+
+const TIMEOUT = "Timeout"
+const GOALIE_PULLED = "Goalie Pulled"
+const GOALIE_RETURNED = "Goalie Returned"
+
+const EMPTY_NET = "EN"
+
+// Each team is allowed one timeout per game.
+const TIMEOUTS_PER_GAME = 1
+
+// Returns the event type for the second letter of a new event code, e.g. "P" in "HP" for
+// a home penalty.
+func EventTypeFromCode(code string) string {
+	switch code {
+	case "P":
+		return PENALTY
+	case "T":
+		return TIMEOUT
+	case "K":
+		return GOALIE_PULLED
+	case "R":
+		return GOALIE_RETURNED
+	case "S":
+		return PENALTY_SHOT
+	default:
+		return GOAL
+	}
+}
+
+func opposingTeam(homeAway string) string {
+	if homeAway == HOME {
+		return AWAY
+	}
+	return HOME
+}
+
+// Works out which goals were scored into an empty net by following when each team pulled
+// and returned its goaltender. Goaltenders are back in net at the start of each period.
+// Returns the IDs of the empty-net goals.
+func EmptyNetGoals(game Game) map[string]bool {
+	events := make([]Event, len(game.Events))
+	copy(events, game.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].GameTime < events[j].GameTime
+	})
+
+	emptyNet := make(map[string]bool)
+	pulled := make(map[string]bool)
+	period := 0
+	for _, event := range events {
+		if event.Period != period || event.EventType == PERIOD_START {
+			pulled = make(map[string]bool)
+			period = event.Period
+		}
+		switch event.EventType {
+		case GOALIE_PULLED:
+			pulled[event.HomeAway] = true
+		case GOALIE_RETURNED:
+			pulled[event.HomeAway] = false
+		case GOAL:
+			if pulled[opposingTeam(event.HomeAway)] {
+				emptyNet[event.ID] = true
+			}
+		}
+	}
+	return emptyNet
+}
+
+// Returns true if the team's goaltender is currently out of the net.
+func (game Game) GoaliePulled(homeAway string) bool {
+	pulled := false
+	period := 0
+	for _, event := range game.Events {
+		if event.Period != period {
+			pulled = false
+			period = event.Period
+		}
+		if event.HomeAway != homeAway {
+			continue
+		}
+		switch event.EventType {
+		case GOALIE_PULLED:
+			pulled = true
+		case GOALIE_RETURNED:
+			pulled = false
+		}
+	}
+	return pulled
+}
+
+func countTimeouts(game Game, homeAway string) int {
+	count := 0
+	for _, event := range game.Events {
+		if event.EventType == TIMEOUT && event.HomeAway == homeAway {
+			count++
+		}
+	}
+	return count
+}
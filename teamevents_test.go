@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func addTeamEvent(game *Game, period int, clockTime EventTime, homeAway string, eventType string) {
+	AddEvent(game, Event{
+		ID:        randomEventId(),
+		Period:    period,
+		ClockTime: clockTime,
+		GameTime:  ClockToGameTime(period, clockTime),
+		HomeAway:  homeAway,
+		EventType: eventType,
+	})
+}
+
+func TestEventTypeFromCode(t *testing.T) {
+	codes := map[string]string{"G": GOAL, "P": PENALTY, "T": TIMEOUT, "K": GOALIE_PULLED, "R": GOALIE_RETURNED}
+	for code, eventType := range codes {
+		if EventTypeFromCode(code) != eventType {
+			t.Errorf("Expected %s for %s, got %s", eventType, code, EventTypeFromCode(code))
+		}
+	}
+}
+
+func TestEmptyNetGoals(t *testing.T) {
+	game := testGame1()
+	addTeamEvent(&game, 3, "01:30", HOME, GOALIE_PULLED)
+	AddGoal(&game, 3, "01:00", AWAY, 98, 0, 0, "Even")
+	addTeamEvent(&game, 3, "00:50", HOME, GOALIE_RETURNED)
+	AddGoal(&game, 3, "00:20", AWAY, 98, 0, 0, "Even")
+
+	emptyNet := EmptyNetGoals(game)
+	if len(emptyNet) != 1 || !emptyNet[game.Events[5].ID] {
+		t.Errorf("Expected only the goal at 01:00 to be empty net, got %v", emptyNet)
+	}
+
+	summary := summarise(game)
+	if summary.AwayEmptyNetGoals != 1 || summary.HomeEmptyNetGoals != 0 {
+		t.Errorf("Unexpected empty net goals: home %d, away %d", summary.HomeEmptyNetGoals, summary.AwayEmptyNetGoals)
+	}
+	if summary.AwayPlayers[98].EmptyNetGoals != 1 {
+		t.Errorf("Empty net goal not counted for scorer: %+v", summary.AwayPlayers[98])
+	}
+}
+
+func TestGoalieBackInNetForNewPeriod(t *testing.T) {
+	game := testGame1()
+	addTeamEvent(&game, 1, "00:10", AWAY, GOALIE_PULLED)
+	AddGoal(&game, 2, "19:00", HOME, 41, 0, 0, "Even")
+
+	if len(EmptyNetGoals(game)) != 0 {
+		t.Error("Goal in a new period counted as empty net")
+	}
+	if game.GoaliePulled(AWAY) {
+		t.Error("Goalie still pulled after the end of the period")
+	}
+}
+
+func TestGoaliePulled(t *testing.T) {
+	game := testGame1()
+	addTeamEvent(&game, 3, "01:30", HOME, GOALIE_PULLED)
+
+	if !game.GoaliePulled(HOME) || game.GoaliePulled(AWAY) {
+		t.Error("Expected only the home goalie to be pulled")
+	}
+}
+
+func TestTimeoutsInSummary(t *testing.T) {
+	game := testGame1()
+	addTeamEvent(&game, 2, "10:00", AWAY, TIMEOUT)
+
+	summary := summarise(game)
+	if summary.AwayTimeouts != 1 || summary.HomeTimeouts != 0 {
+		t.Errorf("Unexpected timeouts: home %d, away %d", summary.HomeTimeouts, summary.AwayTimeouts)
+	}
+
+	addTeamEvent(&game, 3, "10:00", AWAY, TIMEOUT)
+	found := false
+	for _, problem := range ValidateForFinal(game) {
+		if problem == fmt.Sprintf("Away team has more timeouts recorded than the %d allowed", TIMEOUTS_PER_GAME) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Second timeout not reported: %v", ValidateForFinal(game))
+	}
+}
@@ -0,0 +1,24 @@
+// Counts down the game clock display while the server-side clock is running.
+(function () {
+	var display = document.getElementById("clock_time");
+	if (!display || display.dataset.running !== "true") {
+		return;
+	}
+
+	var remaining = parseInt(display.dataset.remaining, 10);
+	var started = Date.now();
+
+	function pad(value) {
+		return (value < 10 ? "0" : "") + value;
+	}
+
+	function tick() {
+		var left = Math.max(0, remaining - Math.floor((Date.now() - started) / 1000));
+		display.textContent = pad(Math.floor(left / 60)) + ":" + pad(left % 60);
+		if (left > 0) {
+			setTimeout(tick, 250);
+		}
+	}
+
+	tick();
+})();
@@ -0,0 +1,204 @@
+// Queues new events on the device when they can't be sent, and sends them to /syncEvents
+// when the connection returns. Each event has an ID made here, so if a batch is sent twice
+// the server only adds the events once.
+(function () {
+	var QUEUE_KEY = "scoresheet-queue";
+
+	if ("serviceWorker" in navigator) {
+		navigator.serviceWorker.register("/sw.js").then(precacheEventForms).catch(function () {});
+	}
+
+	function loadQueue() {
+		try {
+			return JSON.parse(localStorage.getItem(QUEUE_KEY)) || [];
+		} catch (e) {
+			return [];
+		}
+	}
+
+	function saveQueue(queue) {
+		localStorage.setItem(QUEUE_KEY, JSON.stringify(queue));
+	}
+
+	function newEventId() {
+		if (window.crypto && crypto.randomUUID) {
+			return crypto.randomUUID();
+		}
+		return Date.now().toString(36) + "-" + Math.random().toString(36).slice(2, 10);
+	}
+
+	// Form fields as an object of arrays, which is how the server reads them.
+	function formFields(form) {
+		var fields = {};
+		new FormData(form).forEach(function (value, name) {
+			(fields[name] = fields[name] || []).push(value);
+		});
+		return fields;
+	}
+
+	// Saves the new event forms linked from a game page so they can be opened offline.
+	function precacheEventForms() {
+		var links = document.querySelectorAll("a[href^='/newEvent']");
+		if (links.length === 0 || !navigator.serviceWorker.controller) {
+			return;
+		}
+		var urls = Array.prototype.map.call(links, function (link) {
+			return link.getAttribute("href");
+		});
+		navigator.serviceWorker.controller.postMessage({ type: "precache", urls: urls });
+	}
+
+	function showQueue() {
+		var display = document.getElementById("offline_queue");
+		if (!display) {
+			return;
+		}
+		var waiting = loadQueue().filter(function (item) {
+			return item.game_id === display.dataset.game;
+		}).length;
+		display.textContent = waiting === 0 ? "" :
+			waiting + (waiting === 1 ? " event is" : " events are") + " waiting to be sent";
+	}
+
+	// The CSRF token for this session. The token saved with a queued form may be out of date,
+	// as the form could have come from the offline cache, so the current cookie is used instead.
+	function csrfToken() {
+		var match = document.cookie.match(/(?:^|;\s*)_csrf=([^;]*)/);
+		if (match) {
+			return decodeURIComponent(match[1]);
+		}
+		var field = document.querySelector("input[name=_csrf]");
+		return field ? field.value : "";
+	}
+
+	function eventId(item) {
+		return (item.fields.event_id || [""])[0];
+	}
+
+	// Removes the items that have been dealt with, keeping any queued while they were sent.
+	function removeFromQueue(batch) {
+		var sent = batch.map(eventId);
+		saveQueue(loadQueue().filter(function (item) {
+			return sent.indexOf(eventId(item)) < 0;
+		}));
+	}
+
+	function showSyncErrors(messages) {
+		var errors = document.getElementById("error_message");
+		if (errors && messages.length > 0) {
+			errors.textContent = messages.length + " queued event(s) could not be added: " + messages.join(", ");
+		}
+	}
+
+	// Sends the queued events for one game. Resolves to the number of events added and the
+	// reasons any were rejected. Events stay queued if they could not be sent, but are dropped
+	// if the server will never accept them, e.g. because the game has been deleted.
+	function syncGame(gameId, batch, token) {
+		return fetch("/syncEvents", {
+			method: "POST",
+			credentials: "same-origin",
+			headers: { "Content-Type": "application/json", "X-CSRF-Token": token },
+			body: JSON.stringify({
+				game_id: gameId,
+				events: batch.map(function (item) {
+					return item.fields;
+				})
+			})
+		}).then(function (response) {
+			if (response.ok) {
+				return response.json().then(function (result) {
+					removeFromQueue(batch);
+					var rejected = Object.keys(result.rejected || {});
+					return {
+						added: result.added.length,
+						rejected: rejected.map(function (id) {
+							return result.rejected[id];
+						})
+					};
+				});
+			}
+			if (response.status >= 400 && response.status < 500 && response.status !== 408 && response.status !== 429) {
+				removeFromQueue(batch);
+				return { added: 0, rejected: ["game " + gameId + " (" + response.status + ")"] };
+			}
+			return { added: 0, rejected: [] };
+		}).catch(function () {
+			return { added: 0, rejected: [] };
+		});
+	}
+
+	function sync() {
+		var queue = loadQueue();
+		var token = csrfToken();
+		if (queue.length === 0 || !navigator.onLine || token === "") {
+			return;
+		}
+		var batches = {};
+		queue.forEach(function (item) {
+			(batches[item.game_id] = batches[item.game_id] || []).push(item);
+		});
+		Promise.all(Object.keys(batches).map(function (gameId) {
+			return syncGame(gameId, batches[gameId], token);
+		})).then(function (results) {
+			var added = 0;
+			var rejected = [];
+			results.forEach(function (result) {
+				added += result.added;
+				rejected = rejected.concat(result.rejected);
+			});
+			showSyncErrors(rejected);
+			if (added > 0 && rejected.length === 0 && document.getElementById("offline_queue")) {
+				location.reload();
+			} else {
+				showQueue();
+			}
+		});
+	}
+
+	// Sends the new event form with fetch, so that if the connection fails the event can be
+	// kept and sent later instead of being lost.
+	function submitEvent(submit) {
+		var form = submit.target;
+		submit.preventDefault();
+
+		var item = {
+			game_id: form.elements.game_id.value,
+			fields: formFields(form)
+		};
+
+		function queueEvent() {
+			var queue = loadQueue();
+			queue.push(item);
+			saveQueue(queue);
+			location.href = "/game/" + item.game_id;
+		}
+
+		if (!navigator.onLine) {
+			queueEvent();
+			return;
+		}
+		fetch(form.action, {
+			method: "POST",
+			credentials: "same-origin",
+			body: new URLSearchParams(new FormData(form))
+		}).then(function (response) {
+			if (response.ok) {
+				location.href = response.url;
+			} else {
+				form.submit();
+			}
+		}).catch(queueEvent);
+	}
+
+	var eventForm = document.getElementById("event_form");
+	if (eventForm) {
+		// The form may have come from the offline cache, so its event ID could already have
+		// been used. A new ID each time the form is shown still catches a double submit.
+		eventForm.elements.event_id.value = newEventId();
+		eventForm.addEventListener("submit", submitEvent);
+	}
+
+	window.addEventListener("online", sync);
+	showQueue();
+	sync();
+})();
@@ -0,0 +1,71 @@
+// Service worker that keeps the scoresheet usable when the rink Wi-Fi drops. Static files are
+// served from the cache, and pages are fetched from the network when possible, falling back
+// to the last copy seen. New events are queued by offline.js rather than here.
+var CACHE = "scoresheet-v1";
+
+self.addEventListener("install", function () {
+	self.skipWaiting();
+});
+
+self.addEventListener("activate", function (event) {
+	event.waitUntil(caches.keys().then(function (keys) {
+		return Promise.all(keys.filter(function (key) {
+			return key !== CACHE;
+		}).map(function (key) {
+			return caches.delete(key);
+		}));
+	}).then(function () {
+		return self.clients.claim();
+	}));
+});
+
+// Pages can ask for other pages to be cached, e.g. the new event forms linked from a game.
+self.addEventListener("message", function (event) {
+	if (event.data && event.data.type === "precache") {
+		event.waitUntil(caches.open(CACHE).then(function (cache) {
+			return Promise.all(event.data.urls.map(function (url) {
+				return fetch(url, { credentials: "same-origin" }).then(function (response) {
+					if (response.ok) {
+						return cache.put(url, response);
+					}
+				}).catch(function () {});
+			}));
+		}));
+	}
+});
+
+function fromNetwork(request) {
+	return fetch(request).then(function (response) {
+		if (response.ok) {
+			var copy = response.clone();
+			caches.open(CACHE).then(function (cache) {
+				cache.put(request, copy);
+			});
+		}
+		return response;
+	});
+}
+
+self.addEventListener("fetch", function (event) {
+	var request = event.request;
+	var url = new URL(request.url);
+	if (request.method !== "GET" || url.origin !== self.location.origin) {
+		return;
+	}
+
+	if (url.pathname.indexOf("/static/") === 0) {
+		event.respondWith(caches.match(request).then(function (cached) {
+			return cached || fromNetwork(request);
+		}));
+		return;
+	}
+
+	event.respondWith(fromNetwork(request).catch(function () {
+		return caches.match(request).then(function (cached) {
+			return cached || new Response("You are offline and this page has not been saved.", {
+				status: 503,
+				headers: { "Content-Type": "text/plain" }
+			});
+		});
+	}));
+});
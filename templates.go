@@ -0,0 +1,130 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// The page templates and static content are built into the binary.
+//
+//go:embed template
+var embeddedFiles embed.FS
+
+const TEMPLATE_DIR = "template"
+
+// Template renders the html pages. Each page template is parsed together with the base
+// template once, when the server starts. In development mode (TEMPLATE_RELOAD=1) the
+// templates are read from disk for every request instead, so that changes show up without
+// restarting the server.
+type Template struct {
+	templates map[string]map[string]*template.Template // Pages for each language
+	assets    AssetManifest
+	reload    bool
+}
+
+func templateReload() bool {
+	return os.Getenv("TEMPLATE_RELOAD") == "1"
+}
+
+// Returns the template files, from disk in development mode or embedded otherwise.
+func templateFiles(reload bool) fs.FS {
+	if reload {
+		return os.DirFS(TEMPLATE_DIR)
+	}
+	files, err := fs.Sub(embeddedFiles, TEMPLATE_DIR)
+	if err != nil {
+		panic(err)
+	}
+	return files
+}
+
+// Returns the static content served under /static.
+func staticFiles(reload bool) fs.FS {
+	files, err := fs.Sub(templateFiles(reload), "static")
+	if err != nil {
+		panic(err)
+	}
+	return files
+}
+
+// Template functions. The asset function gives the content-hashed URL of a static file, and
+// T translates text into the language the page is being shown in.
+func templateFuncs(assets AssetManifest, lang string) template.FuncMap {
+	return template.FuncMap{
+		"asset":     assets.URL,
+		"T":         translator(lang),
+		"languages": Languages,
+		"themes":    func() []Theme { return Themes },
+	}
+}
+
+func parsePage(files fs.FS, page string, assets AssetManifest, lang string) (*template.Template, error) {
+	return template.New("base.html").Funcs(templateFuncs(assets, lang)).ParseFS(files, "base.html", page)
+}
+
+// Parses all the page templates, returning an error if any of them fail to parse.
+func parseTemplates(files fs.FS, assets AssetManifest, lang string) (map[string]*template.Template, error) {
+	pages, err := fs.Glob(files, "*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]*template.Template)
+	for _, page := range pages {
+		if page == "base.html" {
+			continue
+		}
+		t, err := parsePage(files, page, assets, lang)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", page, err)
+		}
+		templates[strings.TrimSuffix(page, ".html")] = t
+	}
+	return templates, nil
+}
+
+func NewTemplate(reload bool) (*Template, error) {
+	assets, err := buildAssetManifest(staticFiles(reload))
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[string]map[string]*template.Template)
+	for _, language := range Languages() {
+		templates[language.Code], err = parseTemplates(templateFiles(reload), assets, language.Code)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Template{templates: templates, assets: assets, reload: reload}, nil
+}
+
+func (t *Template) lookup(name string, lang string) (*template.Template, error) {
+	if t.reload {
+		assets, err := buildAssetManifest(staticFiles(true))
+		if err != nil {
+			return nil, err
+		}
+		return parsePage(templateFiles(true), name+".html", assets, lang)
+	}
+	page, ok := t.templates[lang][name]
+	if !ok {
+		return nil, fmt.Errorf("unknown template: %s", name)
+	}
+	return page, nil
+}
+
+func (t *Template) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	page, err := t.lookup(name, negotiateLanguage(c))
+	if err != nil {
+		logs.error("Error loading template: %+v", err)
+		return err
+	}
+	return showTemplatePage(page, data, w, c)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestParseAllTemplates(t *testing.T) {
+	renderer, err := NewTemplate(false)
+	if err != nil {
+		t.Fatalf("Templates failed to parse: %v", err)
+	}
+	for _, name := range []string{"index", "game", "newevent", "gamelist", "team"} {
+		if _, err := renderer.lookup(name, DEFAULT_LANGUAGE); err != nil {
+			t.Errorf("Template %s not loaded: %v", name, err)
+		}
+	}
+	if _, ok := renderer.templates[DEFAULT_LANGUAGE]["base"]; ok {
+		t.Error("Base template should not be a page")
+	}
+}
+
+func TestUnknownTemplate(t *testing.T) {
+	if _, err := testTemplates().lookup("nosuchpage", DEFAULT_LANGUAGE); err == nil {
+		t.Error("Expected an error for an unknown template")
+	}
+}
+
+func TestReloadTemplatesFromDisk(t *testing.T) {
+	renderer, err := NewTemplate(true)
+	if err != nil {
+		t.Fatalf("Templates failed to parse from disk: %v", err)
+	}
+	if _, err := renderer.lookup("help", DEFAULT_LANGUAGE); err != nil {
+		t.Errorf("Template not reloaded: %v", err)
+	}
+}
+
+func TestEmbeddedStaticFiles(t *testing.T) {
+	if _, err := fs.Stat(staticFiles(false), "clock.js"); err != nil {
+		t.Errorf("Static file not embedded: %v", err)
+	}
+}
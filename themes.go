@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// The look of the site is chosen from a fixed set of themes. Each theme has a stylesheet
+// in template/static. Visitors can pick a theme, which is kept in the style cookie, and
+// lists can have a default theme for their pages, used when the visitor hasn't picked one.
+
+const DEFAULT_THEME = "simple"
+const STYLE_COOKIE = "scoresheetstyle"
+
+// The style cookie used to hold the stylesheet name, e.g. "scoresheet-simple", and was set
+// for every visitor, so these values are not treated as a choice.
+const LEGACY_STYLE_PREFIX = "scoresheet-"
+
+type Theme struct {
+	Name  string
+	Label string
+}
+
+var Themes = []Theme{
+	{Name: "simple", Label: "Simple"},
+	{Name: "retro", Label: "Retro"},
+	{Name: "original", Label: "Original"},
+	{Name: "high-contrast", Label: "High contrast"},
+	{Name: "dark", Label: "Dark"},
+}
+
+func (theme Theme) Stylesheet() string {
+	return "scoresheet-" + theme.Name
+}
+
+// Returns the theme with the given name.
+func ThemeFor(name string) (Theme, bool) {
+	for _, theme := range Themes {
+		if theme.Name == name {
+			return theme, true
+		}
+	}
+	return Theme{}, false
+}
+
+// Returns the theme name if it is known, or an empty string otherwise.
+func ValidTheme(name string) string {
+	theme, _ := ThemeFor(name)
+	return theme.Name
+}
+
+// Chooses the theme for a page: the visitor's choice if they have made one, then the
+// default for the page (e.g. from a list's settings), then the site default.
+func chooseTheme(c echo.Context, pageDefault string) Theme {
+	if cookie, err := c.Cookie(STYLE_COOKIE); err == nil && !strings.HasPrefix(cookie.Value, LEGACY_STYLE_PREFIX) {
+		if theme, ok := ThemeFor(cookie.Value); ok {
+			return theme
+		}
+	}
+	if theme, ok := ThemeFor(pageDefault); ok {
+		return theme
+	}
+	theme, _ := ThemeFor(DEFAULT_THEME)
+	return theme
+}
+
+func setStyleCookie(themeName string, c echo.Context) {
+	stylecookie := new(http.Cookie)
+	stylecookie.Name = STYLE_COOKIE
+	stylecookie.Path = "/"
+	stylecookie.Value = themeName
+	stylecookie.SameSite = http.SameSiteStrictMode
+	stylecookie.Secure = true
+	stylecookie.Expires = time.Now().Add(2 * 365 * time.Hour)
+	c.SetCookie(stylecookie)
+}
+
+func styleSet(c echo.Context) error {
+	if theme, ok := ThemeFor(c.QueryParam("style")); ok {
+		setStyleCookie(theme.Name, c)
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/")
+}
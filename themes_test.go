@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestThemeFor(t *testing.T) {
+	if theme, ok := ThemeFor("dark"); !ok || theme.Stylesheet() != "scoresheet-dark" {
+		t.Errorf("Unexpected theme %+v", theme)
+	}
+	for _, name := range []string{"", "purple", "../../etc/passwd", "scoresheet-", "scoresheet-retro"} {
+		if _, ok := ThemeFor(name); ok {
+			t.Errorf("Unexpected theme for %q", name)
+		}
+	}
+}
+
+func TestThemeStylesheetsExist(t *testing.T) {
+	for _, theme := range Themes {
+		if _, err := fs.Stat(staticFiles(false), theme.Stylesheet()+".css"); err != nil {
+			t.Errorf("No stylesheet for theme %s: %v", theme.Name, err)
+		}
+	}
+}
+
+func TestChooseTheme(t *testing.T) {
+	wt := webTest(t)
+	if theme := chooseTheme(wt.ec, ""); theme.Name != DEFAULT_THEME {
+		t.Errorf("Expected default theme, got %s", theme.Name)
+	}
+	if theme := chooseTheme(wt.ec, "dark"); theme.Name != "dark" {
+		t.Errorf("Expected page default theme, got %s", theme.Name)
+	}
+
+	wt = webTest(t)
+	wt.req.AddCookie(&http.Cookie{Name: STYLE_COOKIE, Value: "high-contrast"})
+	if theme := chooseTheme(wt.ec, "dark"); theme.Name != "high-contrast" {
+		t.Errorf("Visitor's theme should be used, got %s", theme.Name)
+	}
+
+	wt = webTest(t)
+	wt.req.AddCookie(&http.Cookie{Name: STYLE_COOKIE, Value: "nonsense"})
+	if theme := chooseTheme(wt.ec, ""); theme.Name != DEFAULT_THEME {
+		t.Errorf("Unknown theme in cookie should be ignored, got %s", theme.Name)
+	}
+
+	wt = webTest(t)
+	wt.req.AddCookie(&http.Cookie{Name: STYLE_COOKIE, Value: "scoresheet-simple"})
+	if theme := chooseTheme(wt.ec, "dark"); theme.Name != "dark" {
+		t.Errorf("Old style cookie should not override the page default, got %s", theme.Name)
+	}
+}
+
+func TestStyleSet(t *testing.T) {
+	wt := webTest(t)
+	wt.setQuery("style", "dark")
+
+	styleSet(wt.ec)
+
+	wt.confirmRedirect("/")
+	if cookie := wt.resp.Header().Get("Set-Cookie"); !strings.HasPrefix(cookie, STYLE_COOKIE+"=dark;") {
+		t.Errorf("Expected style cookie, got %s", cookie)
+	}
+
+	wt = webTest(t)
+	wt.setQuery("style", "evil.css?")
+
+	styleSet(wt.ec)
+
+	if wt.resp.Header().Get("Set-Cookie") != "" {
+		t.Error("Unknown style should not be saved")
+	}
+}
+
+func TestListTheme(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("list_id=" + TEST_LIST_ID + "&points_system=IIHF&theme=dark")
+
+	listSettingsPost(wt.ec)
+
+	wt.confirmRedirect("/list/" + TEST_LIST_ID)
+	if list := dataStore.getList(context.TODO(), TEST_LIST_ID); list.Theme != "dark" {
+		t.Errorf("Expected list theme to be saved, got %q", list.Theme)
+	}
+
+	wt = webTest(t)
+	wt.setParam("id", TEST_LIST_ID)
+	defer wt.showBodyOnFail()
+
+	gameListPage(wt.ec)
+
+	wt.confirmSuccessResponse()
+	if !strings.Contains(wt.resp.Body.String(), "/scoresheet-dark.css") {
+		t.Error("List page should use the list's theme")
+	}
+}
+
+func TestListThemeNotValid(t *testing.T) {
+	dataStore = GameStore{datastore: testDataStore()}
+	setupDataStore(dataStore)
+
+	wt := webTest(t)
+	wt.post("list_id=" + TEST_LIST_ID + "&theme=purple")
+
+	listSettingsPost(wt.ec)
+
+	if list := dataStore.getList(context.TODO(), TEST_LIST_ID); list.Theme != "" {
+		t.Errorf("Unknown theme should not be saved, got %q", list.Theme)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -21,6 +22,20 @@ type WebTest struct {
 	failed      bool
 }
 
+var parsedTemplates *Template
+
+// Returns the embedded templates, parsing them the first time they are used.
+func testTemplates() *Template {
+	if parsedTemplates == nil {
+		templates, err := NewTemplate(false)
+		if err != nil {
+			panic(err)
+		}
+		parsedTemplates = templates
+	}
+	return parsedTemplates
+}
+
 func webTest(t *testing.T) *WebTest {
 	wt := WebTest{
 		testContext: t,
@@ -28,11 +43,23 @@ func webTest(t *testing.T) *WebTest {
 	wt.req = httptest.NewRequest(http.MethodGet, "/", nil)
 	wt.resp = httptest.NewRecorder()
 	wt.e = echo.New()
-	wt.e.Renderer = &Template{}
+	wt.e.Renderer = testTemplates()
 	wt.ec = wt.e.NewContext(wt.req, wt.resp)
 	return &wt
 }
 
+// Runs a GET request through the routes of an echo instance, with any headers given as
+// name and value pairs, and returns the response.
+func getPage(e *echo.Echo, url string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	for n := 0; n+1 < len(headers); n += 2 {
+		req.Header.Set(headers[n], headers[n+1])
+	}
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, req)
+	return resp
+}
+
 func (wt *WebTest) setParam(name string, value string) {
 	wt.ec.SetParamNames(name)
 	wt.ec.SetParamValues(value)
@@ -56,14 +83,124 @@ func (wt *WebTest) confirmSuccessResponse() {
 }
 
 func (wt *WebTest) confirmHtmlIncludes(query string, expected string) {
+	text := wt.document().Find(query).Text()
+	if !strings.Contains(text, expected) {
+		wt.failed = true
+		wt.testContext.Errorf("Did not find `%s` in %s", expected, query)
+	}
+}
+
+func (wt *WebTest) document() *goquery.Document {
 	if wt.doc == nil {
 		wt.doc, _ = goquery.NewDocumentFromReader(bytes.NewReader(wt.resp.Body.Bytes()))
 	}
-	text := wt.doc.Find(query).Text()
-	if !strings.Contains(text, expected) {
+	return wt.doc
+}
+
+// Checks the page for common accessibility problems, such as form fields without labels,
+// tables without a name or header scope, and links or buttons without any text.
+func (wt *WebTest) confirmAccessible() {
+	for _, problem := range accessibilityProblems(wt.document()) {
 		wt.failed = true
-		wt.testContext.Errorf("Did not find `%s` in %s", expected, query)
+		wt.testContext.Errorf("Accessibility: %s", problem)
+	}
+}
+
+func accessibilityProblems(doc *goquery.Document) []string {
+	var problems []string
+	report := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if lang, _ := doc.Find("html").Attr("lang"); lang == "" {
+		report("page has no language")
+	}
+	if doc.Find("main").Length() != 1 {
+		report("page should have one main landmark")
+	}
+
+	ids := make(map[string]bool)
+	doc.Find("[id]").Each(func(_ int, element *goquery.Selection) {
+		id, _ := element.Attr("id")
+		if ids[id] {
+			report("id %s is used more than once", id)
+		}
+		ids[id] = true
+	})
+
+	doc.Find("[aria-labelledby], [aria-describedby]").Each(func(_ int, element *goquery.Selection) {
+		for _, attr := range []string{"aria-labelledby", "aria-describedby"} {
+			if ref, ok := element.Attr(attr); ok && !ids[ref] {
+				report("%s refers to missing id %s", attr, ref)
+			}
+		}
+	})
+
+	doc.Find("img").Each(func(_ int, img *goquery.Selection) {
+		if _, ok := img.Attr("alt"); !ok {
+			src, _ := img.Attr("src")
+			report("image %s has no alt text", src)
+		}
+	})
+
+	doc.Find("input, select, textarea").Each(func(_ int, field *goquery.Selection) {
+		inputType, _ := field.Attr("type")
+		switch inputType {
+		case "hidden":
+			return
+		case "submit", "button", "reset":
+			if value, _ := field.Attr("value"); strings.TrimSpace(value) == "" && !hasAriaName(field) {
+				report("button has no text")
+			}
+			return
+		}
+		if hasAriaName(field) || field.ParentsFiltered("label").Length() > 0 {
+			return
+		}
+		id, _ := field.Attr("id")
+		if id == "" || doc.Find(fmt.Sprintf("label[for=%q]", id)).Length() == 0 {
+			name, _ := field.Attr("name")
+			report("form field %s has no label", name)
+		}
+	})
+
+	doc.Find("a, button").Each(func(_ int, link *goquery.Selection) {
+		text := strings.TrimSpace(link.Text())
+		alt, _ := link.Find("img").Attr("alt")
+		if text == "" && alt == "" && !hasAriaName(link) {
+			href, _ := link.Attr("href")
+			report("link or button %s has no text", href)
+		}
+	})
+
+	doc.Find("[tabindex]").Each(func(_ int, element *goquery.Selection) {
+		if index, _ := element.Attr("tabindex"); index != "0" && index != "-1" {
+			report("tabindex %s changes the keyboard order", index)
+		}
+	})
+
+	doc.Find("table").Each(func(_ int, table *goquery.Selection) {
+		id, _ := table.Attr("id")
+		if table.Find("caption").Length() == 0 && !hasAriaName(table) {
+			report("table %s has no caption or label", id)
+		}
+		table.Find("th").Each(func(_ int, header *goquery.Selection) {
+			if _, ok := header.Attr("scope"); !ok {
+				report("header %q in table %s has no scope", strings.TrimSpace(header.Text()), id)
+			}
+		})
+	})
+
+	return problems
+}
+
+func hasAriaName(element *goquery.Selection) bool {
+	for _, attr := range []string{"aria-label", "aria-labelledby"} {
+		if value, _ := element.Attr(attr); strings.TrimSpace(value) != "" {
+			return true
+		}
 	}
+	return false
 }
 
 func (wt *WebTest) confirmRedirect(target string) {